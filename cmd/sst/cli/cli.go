@@ -162,6 +162,9 @@ func (c *Command) init(parsed map[string]interface{}) {
 		}
 		if f.Type == "string" {
 			parsed[f.Name] = flag.String(f.Name, "", "")
+			if f.NoValue != "" {
+				flag.Lookup(f.Name).NoOptDefVal = f.NoValue
+			}
 		}
 
 		if f.Type == "bool" {
@@ -207,6 +210,9 @@ type Flag struct {
 	Name        string      `json:"name"`
 	Type        string      `json:"type"`
 	Description Description `json:"description"`
+	// NoValue is used when a string flag is passed without a value, so it can
+	// be used like `--flag` or `--flag=value`.
+	NoValue string `json:"-"`
 }
 
 type CommandPath []Command
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project"
+	"golang.org/x/term"
+)
+
+func isInteractive() bool {
+	return os.Getenv("CI") == "" && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// confirmDestroy asks for the name of the stage to be typed in before a
+// protected stage deletes or replaces resources. Without a terminal, like in
+// CI, it fails.
+func confirmDestroy(u *ui.UI, stage string) func([]project.PlannedChange) (bool, error) {
+	return func(changes []project.PlannedChange) (bool, error) {
+		if !isInteractive() {
+			return false, util.NewReadableError(nil, fmt.Sprintf("The %v stage is protected. Pass in --allow-destroy to delete or replace resources without a prompt.", stage))
+		}
+		u.Pause()
+		defer u.Resume()
+
+		fmt.Println()
+		fmt.Println(ui.TEXT_DANGER_BOLD.Render(ui.IconX) + ui.TEXT_NORMAL_BOLD.Render("  Protected stage ") + ui.TEXT_DIM.Render(stage))
+		fmt.Println()
+		for _, change := range changes {
+			fmt.Println("   " + ui.TEXT_DANGER.Render(fmt.Sprintf("%-20s", change.Op)) + ui.TEXT_NORMAL.Render(u.FormatURN(change.URN)))
+		}
+		fmt.Println()
+
+		var input string
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().Title(fmt.Sprintf(" Type %v to delete or replace these resources", stage)).Prompt(" > ").Value(&input),
+			),
+		).WithTheme(huh.ThemeCatppuccin()).Run()
+		if err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return false, nil
+			}
+			return false, err
+		}
+		return input == stage, nil
+	}
+}
+
+// confirmPlan prints the changes in the plan and asks for approval before
+// they are deployed. With yes set it only prints them.
+func confirmPlan(u *ui.UI, yes bool) func(*project.Plan) (bool, error) {
+	return func(plan *project.Plan) (bool, error) {
+		if !yes && !isInteractive() {
+			return false, util.NewReadableError(nil, "Cannot ask to confirm the deploy without a terminal. Pass in --yes to deploy without a prompt.")
+		}
+		u.Pause()
+		defer u.Resume()
+
+		fmt.Println()
+		printDiff(u, plan.Outputs, plan.Parents)
+		if yes || len(plan.Changes) == 0 {
+			return true, nil
+		}
+
+		var confirmed bool
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().Title(" Deploy these changes?").Affirmative("Yes").Negative("No").Value(&confirmed),
+			),
+		).WithTheme(huh.ThemeCatppuccin()).Run()
+		if err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return false, nil
+			}
+			return false, err
+		}
+		return confirmed, nil
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/project/provider"
+	"github.com/sst/ion/pkg/server"
+	"golang.org/x/sync/errgroup"
+)
+
+var CmdCost = &cli.Command{
+	Name: "cost",
+	Description: cli.Description{
+		Short: "Estimate the monthly cost of your app",
+		Long: strings.Join([]string{
+			"Estimates the monthly cost of the resources in the state of a stage, grouped by component.",
+			"",
+			"```bash frame=\"none\"",
+			"sst cost --stage production",
+			"```",
+			"",
+			"Optionally, preview your changes and see how the estimate would change if you deployed them.",
+			"",
+			"```bash frame=\"none\"",
+			"sst cost --diff",
+			"```",
+			"",
+			"The estimate is based on the resource types and their inputs, like instance classes, memory sizes, or NAT gateways. Prices come from a pricing file that ships with the CLI, so this works offline. Usage based charges, like requests or data transfer, are not included.",
+			"",
+			"You can add or override prices with a `sst.pricing.json` next to your `sst.config.ts`. The rules for a type replace the built-in ones.",
+			"",
+			"```json title=\"sst.pricing.json\"",
+			"[",
+			"  {",
+			"    \"type\": \"aws:rds/instance:Instance\",",
+			"    \"input\": \"instanceClass\",",
+			"    \"prices\": { \"db.t4g.micro\": 11.68 }",
+			"  },",
+			"  { \"type\": \"aws:ec2/natGateway:NatGateway\", \"monthly\": 32.85 }",
+			"]",
+			"```",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "diff",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Compare to the changes that will be deployed",
+				Long:  "Preview the changes that will be deployed and show the estimate before and after.",
+			},
+		},
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Use json to print the estimate as JSON",
+				Long:  "Use `json` to print the estimate as JSON.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		rules, err := p.PriceRules()
+		if err != nil {
+			return err
+		}
+
+		current := []project.CostResource{}
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if !errors.Is(err, provider.ErrStateNotFound) {
+				return err
+			}
+			return util.NewReadableError(err, "Stage \""+p.App().Stage+"\" has not been deployed. Deploy it first with `sst deploy`.")
+		}
+		current = project.CostResources(complete.Resources)
+
+		var planned []project.CostResource
+		if c.Bool("diff") {
+			// the preview is only shown when printing a table so json stays valid
+			steps, err := previewSteps(c, p, c.String("format") != "json")
+			if err != nil {
+				return err
+			}
+			planned = project.PlannedCostResources(current, steps)
+		}
+
+		before := project.ComponentCosts(rules, current)
+		if planned == nil {
+			if c.String("format") == "json" {
+				return printJSON(before)
+			}
+			rows := [][]string{{"Component", "Monthly"}}
+			total := 0.0
+			for _, cost := range before {
+				rows = append(rows, []string{componentName(cost.URN), formatCost(cost.Monthly)})
+				total += cost.Monthly
+			}
+			rows = append(rows, []string{"Total", formatCost(total)})
+			printTable(rows, func(row []string) bool { return row[0] == "Total" })
+			printCostNote()
+			return nil
+		}
+
+		after := project.ComponentCosts(rules, planned)
+		if c.String("format") == "json" {
+			return printJSON(map[string]interface{}{
+				"current": before,
+				"planned": after,
+			})
+		}
+		totals := map[string][2]float64{}
+		order := []string{}
+		for i, costs := range [][]project.ComponentCost{before, after} {
+			for _, cost := range costs {
+				value, ok := totals[cost.URN]
+				if !ok {
+					order = append(order, cost.URN)
+				}
+				value[i] = cost.Monthly
+				totals[cost.URN] = value
+			}
+		}
+		rows := [][]string{{"Component", "Current", "Planned", "Delta"}}
+		sum := [2]float64{}
+		for _, urn := range order {
+			value := totals[urn]
+			sum[0] += value[0]
+			sum[1] += value[1]
+			rows = append(rows, []string{
+				componentName(urn),
+				formatCost(value[0]),
+				formatCost(value[1]),
+				formatDelta(value[1] - value[0]),
+			})
+		}
+		rows = append(rows, []string{"Total", formatCost(sum[0]), formatCost(sum[1]), formatDelta(sum[1] - sum[0])})
+		printTable(rows, func(row []string) bool { return row[0] == "Total" || row[3] != "" })
+		printCostNote()
+		return nil
+	},
+}
+
+// previewSteps runs a diff and returns the steps it would take. The progress of
+// the diff is only printed if show is set.
+func previewSteps(c *cli.Cli, p *project.Project, show bool) ([]apitype.StepEventMetadata, error) {
+	s, err := server.New()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(c.Context)
+	defer cancel()
+	var wg errgroup.Group
+	wg.Go(func() error {
+		return s.Start(ctx, p)
+	})
+	steps := []apitype.StepEventMetadata{}
+	failures := []string{}
+	var u *ui.UI
+	if show {
+		u = ui.New(c.Context)
+		defer u.Destroy()
+	}
+	events := bus.SubscribeAll()
+	wg.Go(func() error {
+		for evt := range events {
+			if u != nil {
+				u.Event(evt)
+			}
+			switch evt := evt.(type) {
+			case *apitype.ResourcePreEvent:
+				steps = append(steps, evt.Metadata)
+			case *project.CompleteEvent:
+				for _, item := range evt.Errors {
+					failures = append(failures, item.Message)
+				}
+			}
+		}
+		return nil
+	})
+	err = p.Run(ctx, &project.StackInput{
+		Command:    "diff",
+		ServerPort: s.Port,
+		Verbose:    c.Bool("verbose"),
+	})
+	cancel()
+	close(events)
+	wg.Wait()
+	if err != nil {
+		if errors.Is(err, project.ErrStackRunFailed) && !show && len(failures) > 0 {
+			return nil, util.NewReadableError(err, "Preview failed\n"+strings.Join(failures, "\n"))
+		}
+		return nil, err
+	}
+	return steps, nil
+}
+
+func componentName(urn string) string {
+	parsed := resource.URN(urn)
+	if !parsed.IsValid() {
+		return urn
+	}
+	return parsed.Name() + " (" + string(parsed.Type()) + ")"
+}
+
+func formatCost(value float64) string {
+	return fmt.Sprintf("$%.2f", value)
+}
+
+func formatDelta(value float64) string {
+	if value > -0.005 && value < 0.005 {
+		return ""
+	}
+	if value < 0 {
+		return fmt.Sprintf("-$%.2f", -value)
+	}
+	return fmt.Sprintf("+$%.2f", value)
+}
+
+func printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func printCostNote() {
+	fmt.Println()
+	fmt.Println(ui.TEXT_DIM.Render("Estimates are monthly and exclude usage based charges."))
+}
@@ -1,28 +1,76 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sst/ion/cmd/sst/cli"
 	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
 	"github.com/sst/ion/pkg/bus"
 	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/project/provider"
 	"github.com/sst/ion/pkg/server"
 	"golang.org/x/sync/errgroup"
 )
 
+// updateExpiry sets the stage to expire after the ttl. Without one, the
+// expiry of an earlier deploy is cleared so the stage is kept.
+func updateExpiry(backend provider.Home, app, stage string, ttl time.Duration) error {
+	metadata, err := provider.GetMetadata(backend, app, stage)
+	if err != nil {
+		return err
+	}
+	if ttl <= 0 && metadata.Expires == nil {
+		return nil
+	}
+	metadata.Expires = nil
+	if ttl > 0 {
+		expires := time.Now().Add(ttl).UTC()
+		metadata.Expires = &expires
+	}
+	return provider.PutMetadata(backend, app, stage, metadata)
+}
+
 func CmdDeploy(c *cli.Cli) error {
+	var ttl time.Duration
+	if c.String("ttl") != "" {
+		parsed, err := time.ParseDuration(c.String("ttl"))
+		if err != nil || parsed <= 0 {
+			return util.NewReadableError(err, "Invalid ttl \""+c.String("ttl")+"\". Use a duration like 72h.")
+		}
+		ttl = parsed
+	}
+
 	p, err := c.InitProject()
 	if err != nil {
 		return err
 	}
 	defer p.Cleanup()
 
+	err = updateExpiry(p.Backend(), p.App().Name, p.App().Stage, ttl)
+	if err != nil {
+		return util.NewReadableError(err, "Could not set the expiry of the stage")
+	}
+
 	target := []string{}
 	if c.String("target") != "" {
 		target = strings.Split(c.String("target"), ",")
 	}
 
+	var complete *project.CompleteEvent
+	if c.Bool("profile") {
+		defer func() {
+			if complete != nil {
+				printProfile(p, complete)
+			}
+		}()
+	}
+
 	var wg errgroup.Group
 	defer wg.Wait()
 	out := make(chan interface{})
@@ -41,19 +89,82 @@ func CmdDeploy(c *cli.Cli) error {
 	wg.Go(func() error {
 		for evt := range events {
 			ui.Event(evt)
+			if evt, ok := evt.(*project.CompleteEvent); ok && !evt.Old {
+				complete = evt
+			}
 		}
 		return nil
 	})
 	defer ui.Destroy()
 	defer c.Cancel()
-	err = p.Run(c.Context, &project.StackInput{
-		Command:    "deploy",
-		Target:     target,
-		ServerPort: s.Port,
-		Verbose:    c.Bool("verbose"),
-	})
+	input := &project.StackInput{
+		Command:        "deploy",
+		Target:         target,
+		ServerPort:     s.Port,
+		Verbose:        c.Bool("verbose"),
+		AllowDestroy:   c.Bool("allow-destroy"),
+		ConfirmDestroy: confirmDestroy(ui, p.App().Stage),
+	}
+	if c.Bool("confirm") {
+		input.ConfirmPlan = confirmPlan(ui, c.Bool("yes"))
+	}
+	err = p.Run(c.Context, input)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func printProfile(p *project.Project, complete *project.CompleteEvent) {
+	if len(complete.Timings) == 0 {
+		return
+	}
+	slowest := append([]project.ResourceTiming{}, complete.Timings...)
+	sort.Slice(slowest, func(i, j int) bool {
+		return slowest[i].Duration() > slowest[j].Duration()
+	})
+	if len(slowest) > 10 {
+		slowest = slowest[:10]
+	}
+	formatDuration := func(duration time.Duration) string {
+		return fmt.Sprintf("%8s", duration.Round(100*time.Millisecond))
+	}
+
+	fmt.Println(ui.TEXT_HIGHLIGHT_BOLD.Render("➜") + ui.TEXT_NORMAL_BOLD.Render("  Slowest resources"))
+	fmt.Println()
+	for _, timing := range slowest {
+		fmt.Println("   " + ui.TEXT_DIM.Render(formatDuration(timing.Duration())) + "  " + ui.TEXT_NORMAL.Render(formatProfileURN(timing.URN)) + ui.TEXT_DIM.Render(" "+string(timing.Op)))
+	}
+	fmt.Println()
+
+	path := project.CriticalPath(complete.Timings, complete.Resources)
+	total := path[len(path)-1].End.Sub(path[0].Start)
+	fmt.Println(ui.TEXT_HIGHLIGHT_BOLD.Render("➜") + ui.TEXT_NORMAL_BOLD.Render("  Critical path ") + ui.TEXT_DIM.Render(total.Round(100*time.Millisecond).String()))
+	fmt.Println()
+	for _, timing := range path {
+		fmt.Println("   " + ui.TEXT_DIM.Render(formatDuration(timing.Duration())) + "  " + ui.TEXT_NORMAL.Render(formatProfileURN(timing.URN)))
+	}
+	fmt.Println()
+
+	chrome, err := project.ChromeTrace(complete.Timings)
+	if err != nil {
+		return
+	}
+	otlp, err := project.OTLPTrace(p.App().Name, p.App().Stage, "deploy", complete.Timings)
+	if err != nil {
+		return
+	}
+	chromePath := filepath.Join(p.PathLog(""), "profile.chrome.json")
+	otlpPath := filepath.Join(p.PathLog(""), "profile.otlp.json")
+	if os.WriteFile(chromePath, chrome, 0644) != nil || os.WriteFile(otlpPath, otlp, 0644) != nil {
+		return
+	}
+	fmt.Println(ui.TEXT_DIM.Render("   Chrome trace: " + chromePath))
+	fmt.Println(ui.TEXT_DIM.Render("   OTLP trace:   " + otlpPath))
+	fmt.Println()
+}
+
+func formatProfileURN(urn string) string {
+	splits := strings.Split(urn, "::")
+	return splits[len(splits)-1] + " " + ui.TEXT_DIM.Render(splits[len(splits)-2])
+}
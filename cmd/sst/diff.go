@@ -31,6 +31,7 @@ func CmdDiff(c *cli.Cli) error {
 	var wg errgroup.Group
 	defer wg.Wait()
 	outputs := []*apitype.ResOutputsEvent{}
+	parents := map[string]string{}
 	u := ui.New(c.Context)
 	s, err := server.New()
 	if err != nil {
@@ -49,6 +50,12 @@ func CmdDiff(c *cli.Cli) error {
 			switch evt := evt.(type) {
 			case *apitype.ResOutputsEvent:
 				outputs = append(outputs, evt)
+			case *apitype.ResourcePreEvent:
+				if evt.Metadata.New != nil && evt.Metadata.New.Parent != "" {
+					parents[evt.Metadata.URN] = evt.Metadata.New.Parent
+				} else if evt.Metadata.Old != nil && evt.Metadata.Old.Parent != "" {
+					parents[evt.Metadata.URN] = evt.Metadata.Old.Parent
+				}
 			}
 		}
 		return nil
@@ -65,13 +72,35 @@ func CmdDiff(c *cli.Cli) error {
 	if err != nil {
 		return err
 	}
+	printDiff(u, outputs, parents)
+	return nil
+}
+
+// printDiff prints the changes along with their detailed diff. Resources are
+// grouped by the component they belong to.
+func printDiff(u *ui.UI, outputs []*apitype.ResOutputsEvent, parents map[string]string) {
+	component := func(urn string) string {
+		for {
+			parent, ok := parents[urn]
+			if !ok || strings.Contains(parent, "pulumi:pulumi:Stack") {
+				return urn
+			}
+			urn = parent
+		}
+	}
+	sorted := append([]*apitype.ResOutputsEvent{}, outputs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return component(sorted[i].Metadata.URN) < component(sorted[j].Metadata.URN)
+	})
+	outputs = sorted
+
 	if len(outputs) == 0 {
 		fmt.Println(
 			ui.TEXT_HIGHLIGHT_BOLD.Render("➜"),
 			ui.TEXT_NORMAL_BOLD.Render(" No changes"),
 		)
 		fmt.Println()
-		return nil
+		return
 	}
 	for _, output := range outputs {
 		icon := ""
@@ -149,5 +178,4 @@ func CmdDiff(c *cli.Cli) error {
 		}
 		fmt.Println()
 	}
-	return nil
 }
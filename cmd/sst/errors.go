@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/pkg/project"
+)
+
+var CmdCommonErrors = &cli.Command{
+	Name: "common-errors",
+	Description: cli.Description{
+		Short: "List the rules for common errors",
+		Long: strings.Join([]string{
+			"Lists the rules that are used to add help to common errors.",
+			"",
+			"Along with the built in rules, you can add your own in a `sst.errors.json` file next to your `sst.config.ts`.",
+			"",
+			"```json title=\"sst.errors.json\"",
+			"[",
+			"  {",
+			"    \"code\": \"MigrationLock\",",
+			"    \"message\": \"Migration table is locked\",",
+			"    \"severity\": \"warning\",",
+			"    \"match\": {",
+			"      \"message\": \"table (?P<table>\\\\w+) is locked\",",
+			"      \"type\": \"^sst:aws:Function\"",
+			"    },",
+			"    \"short\": [\"Run `pnpm db:unlock ${table}` and deploy again.\"]",
+			"  }",
+			"]",
+			"```",
+			"",
+			"Each of `message`, `urn`, and `type` in `match` is a regular expression and all the ones that are set have to match.",
+			"Named groups can be used in the help as `${name}`. The `severity` is one of `error`, `warning`, or `info`.",
+			"",
+			"To print the rules as JSON.",
+			"",
+			"```bash frame=\"none\"",
+			"sst common-errors --format json",
+			"```",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Use json to print the rules as JSON",
+				Long:  "Use `json` to print the rules as JSON.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		rules := project.CommonErrors
+		if cfgPath, err := project.Discover(); err == nil {
+			rules, err = project.LoadCommonErrors(project.ResolveCommonErrorsPath(cfgPath))
+			if err != nil {
+				return err
+			}
+		}
+
+		if c.String("format") == "json" {
+			data, err := json.MarshalIndent(rules, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		for _, rule := range rules {
+			style := ui.TEXT_DANGER_BOLD
+			if rule.Severity == "warning" {
+				style = ui.TEXT_WARNING_BOLD
+			}
+			if rule.Severity == "info" {
+				style = ui.TEXT_INFO_BOLD
+			}
+			fmt.Println(style.Render(rule.Code) + ui.TEXT_DIM.Render(" "+rule.Severity+" · "+rule.Source))
+			fmt.Println(ui.TEXT_NORMAL.Render("   " + rule.Message))
+			for _, line := range rule.Short {
+				fmt.Println(ui.TEXT_DIM.Render("   " + line))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
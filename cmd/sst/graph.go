@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+var CmdGraph = &cli.Command{
+	Name: "graph",
+	Description: cli.Description{
+		Short: "Export the resource graph of your app",
+		Long: strings.Join([]string{
+			"Prints the resources in your app as a graph, as they were last deployed.",
+			"",
+			"It includes the component tree, where each component points to its children, and the dependencies between resources.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --stage production > graph.dot",
+			"```",
+			"",
+			"The graph can be formatted as `dot`, `mermaid`, or `json`. By default, it prints `dot` that can be rendered with Graphviz.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --format mermaid",
+			"```",
+			"",
+			"Optionally, only include the resources that match a filter along with everything that depends on them. This is the same set of resources that `--target` pulls in.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --filter MyBucket",
+			"```",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "One of dot, mermaid, or json",
+				Long:  "The format to print the graph in. One of `dot`, `mermaid`, or `json`.",
+			},
+		},
+		{
+			Name: "filter",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only include resources that match and their dependents",
+				Long:  "Only include resources whose URN contains the filter, along with the resources that depend on them.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst graph --format mermaid",
+			Description: cli.Description{
+				Short: "Print the graph as a Mermaid flowchart",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		format := c.String("format")
+		if format == "" {
+			format = "dot"
+		}
+		if format != "dot" && format != "mermaid" && format != "json" {
+			return util.NewReadableError(nil, "Invalid format \""+format+"\". Must be one of dot, mermaid, or json.")
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return util.NewReadableError(err, "Stage \""+p.App().Stage+"\" has not been deployed")
+			}
+			return err
+		}
+
+		graph := newGraph(complete.Resources, c.String("filter"))
+		switch format {
+		case "mermaid":
+			fmt.Print(graph.Mermaid())
+		case "json":
+			bytes, err := json.MarshalIndent(graph, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bytes))
+		default:
+			fmt.Print(graph.Dot())
+		}
+		return nil
+	},
+}
+
+type graphNode struct {
+	URN    string `json:"urn"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
+}
+
+type graphEdge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Kind       string   `json:"kind"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+type graph struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+func newGraph(resources []apitype.ResourceV3, filter string) *graph {
+	included := map[string]bool{}
+	for _, resource := range resources {
+		if strings.HasPrefix(string(resource.Type), "pulumi:") {
+			continue
+		}
+		included[string(resource.URN)] = true
+	}
+
+	if filter != "" {
+		dependents := map[string][]string{}
+		for _, resource := range resources {
+			for _, dep := range resource.Dependencies {
+				dependents[string(dep)] = append(dependents[string(dep)], string(resource.URN))
+			}
+			if resource.Parent != "" {
+				dependents[string(resource.Parent)] = append(dependents[string(resource.Parent)], string(resource.URN))
+			}
+		}
+		matched := map[string]bool{}
+		queue := []string{}
+		for urn := range included {
+			if strings.Contains(urn, filter) {
+				matched[urn] = true
+				queue = append(queue, urn)
+			}
+		}
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			for _, dependent := range dependents[next] {
+				if included[dependent] && !matched[dependent] {
+					matched[dependent] = true
+					queue = append(queue, dependent)
+				}
+			}
+		}
+		included = matched
+	}
+
+	result := &graph{
+		Nodes: []graphNode{},
+		Edges: []graphEdge{},
+	}
+	for _, resource := range resources {
+		urn := string(resource.URN)
+		if !included[urn] {
+			continue
+		}
+		node := graphNode{
+			URN:  urn,
+			Type: string(resource.Type),
+			Name: resource.URN.Name(),
+		}
+		if included[string(resource.Parent)] {
+			node.Parent = string(resource.Parent)
+			result.Edges = append(result.Edges, graphEdge{
+				From: node.Parent,
+				To:   urn,
+				Kind: "parent",
+			})
+		}
+		result.Nodes = append(result.Nodes, node)
+
+		properties := map[string][]string{}
+		for key, deps := range resource.PropertyDependencies {
+			for _, dep := range deps {
+				properties[string(dep)] = append(properties[string(dep)], string(key))
+			}
+		}
+		seen := map[string]bool{}
+		for _, dep := range resource.Dependencies {
+			if !included[string(dep)] || seen[string(dep)] || dep == resource.Parent {
+				continue
+			}
+			seen[string(dep)] = true
+			props := properties[string(dep)]
+			sort.Strings(props)
+			result.Edges = append(result.Edges, graphEdge{
+				From:       urn,
+				To:         string(dep),
+				Kind:       "dependency",
+				Properties: props,
+			})
+		}
+	}
+	return result
+}
+
+func (g *graph) label(node graphNode) string {
+	return node.Name + "\n" + node.Type
+}
+
+func (g *graph) Dot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		sb.WriteString(fmt.Sprintf("  %q [label=%q];\n", node.URN, g.label(node)))
+	}
+	for _, edge := range g.Edges {
+		attrs := []string{}
+		if edge.Kind == "parent" {
+			attrs = append(attrs, "style=dashed", "arrowhead=none")
+		}
+		if len(edge.Properties) > 0 {
+			attrs = append(attrs, fmt.Sprintf("label=%q", strings.Join(edge.Properties, ", ")))
+		}
+		line := fmt.Sprintf("  %q -> %q", edge.From, edge.To)
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		sb.WriteString(line + ";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *graph) Mermaid() string {
+	ids := map[string]string{}
+	escape := strings.NewReplacer(`"`, "#quot;", "\n", "<br>")
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for index, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", index)
+		ids[node.URN] = id
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", id, escape.Replace(g.label(node))))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Kind == "parent" {
+			arrow = "-.-"
+		}
+		if len(edge.Properties) > 0 {
+			arrow += "|" + escape.Replace(strings.Join(edge.Properties, ", ")) + "|"
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s %s\n", ids[edge.From], arrow, ids[edge.To]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+var graphResources = []apitype.ResourceV3{
+	{URN: "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev", Type: "pulumi:pulumi:Stack"},
+	{URN: "urn:pulumi:dev::app::sst:aws:Bucket::Assets", Type: "sst:aws:Bucket", Parent: "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev"},
+	{URN: "urn:pulumi:dev::app::sst:aws:Bucket$aws:s3/bucketV2:BucketV2::AssetsBucket", Type: "aws:s3/bucketV2:BucketV2", Parent: "urn:pulumi:dev::app::sst:aws:Bucket::Assets"},
+	{
+		URN:          "urn:pulumi:dev::app::sst:aws:Function::Api",
+		Type:         "sst:aws:Function",
+		Dependencies: []resource.URN{"urn:pulumi:dev::app::sst:aws:Bucket$aws:s3/bucketV2:BucketV2::AssetsBucket"},
+		PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+			"environment": {"urn:pulumi:dev::app::sst:aws:Bucket$aws:s3/bucketV2:BucketV2::AssetsBucket"},
+		},
+	},
+	{URN: "urn:pulumi:dev::app::sst:aws:Queue::Jobs", Type: "sst:aws:Queue"},
+}
+
+func TestGraphEdges(t *testing.T) {
+	result := newGraph(graphResources, "")
+	if len(result.Nodes) != 4 {
+		t.Fatalf("Expected 4 nodes, got %v", len(result.Nodes))
+	}
+	expected := []graphEdge{
+		{From: "urn:pulumi:dev::app::sst:aws:Bucket::Assets", To: "urn:pulumi:dev::app::sst:aws:Bucket$aws:s3/bucketV2:BucketV2::AssetsBucket", Kind: "parent"},
+		{From: "urn:pulumi:dev::app::sst:aws:Function::Api", To: "urn:pulumi:dev::app::sst:aws:Bucket$aws:s3/bucketV2:BucketV2::AssetsBucket", Kind: "dependency", Properties: []string{"environment"}},
+	}
+	if !reflect.DeepEqual(result.Edges, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Edges)
+	}
+}
+
+func TestGraphFilter(t *testing.T) {
+	result := newGraph(graphResources, "Assets")
+	names := []string{}
+	for _, node := range result.Nodes {
+		names = append(names, node.Name)
+	}
+	expected := []string{"Assets", "AssetsBucket", "Api"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
+	"github.com/sst/ion/cmd/sst/mosaic/local"
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/project/provider"
+	"github.com/sst/ion/pkg/runtime"
+	"github.com/sst/ion/pkg/runtime/lambda"
+	"github.com/sst/ion/pkg/server"
+)
+
+var CmdInvoke = &cli.Command{
+	Name: "invoke",
+	Description: cli.Description{
+		Short: "Run a function locally with a payload",
+		Long: strings.Join([]string{
+			"Builds a function and runs it locally with the given payload, without going through a real trigger.",
+			"",
+			"```bash frame=\"none\"",
+			"sst invoke MyFunction --payload event.json",
+			"```",
+			"",
+			"The function is looked up by its name in the state of the stage. It runs with its links and environment, the same way it does in `sst dev`.",
+			"",
+			"Pass in `-` to read the payload from stdin.",
+			"",
+			"```bash frame=\"none\"",
+			"echo '{\"id\": 1}' | sst invoke MyFunction --payload -",
+			"```",
+			"",
+			"Or start from a sample event. Any payload you pass in is merged on top of it.",
+			"",
+			"```bash frame=\"none\"",
+			"sst invoke MyFunction --event-template sqs",
+			"```",
+			"",
+			"The templates are `apigw`, `dynamodb`, `eventbridge`, `s3`, `schedule`, `sns`, and `sqs`.",
+			"",
+			"If `sst dev` is running for the stage, the function is invoked through it and its logs show up there.",
+			"",
+			"Every invocation in `sst dev` is recorded. To replay one against your current code, pass in its request ID. It's printed along with the error when an invocation fails.",
+			"",
+			"```bash frame=\"none\"",
+			"sst invoke --replay 8c1c1b0e-2f0a-4a8e-9d3c-6f1e0b7a1d2c",
+			"```",
+		}, "\n"),
+	},
+	Args: []cli.Argument{
+		{
+			Name: "function",
+			Description: cli.Description{
+				Short: "The name of the function",
+				Long:  "The name of the function. Not needed with `--replay`.",
+			},
+		},
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "payload",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Path to a JSON payload, or - for stdin",
+				Long:  "Path to a JSON file to use as the payload, or `-` to read it from stdin.",
+			},
+		},
+		{
+			Name: "event-template",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Start from a sample event",
+				Long:  "Start from a sample event, like `apigw`, `sqs`, or `s3`.",
+			},
+		},
+		{
+			Name: "replay",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Replay a recorded invocation",
+				Long:  "Replay an invocation recorded by `sst dev` with the same input, given its request ID.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		payload, err := readPayload(c.String("payload"))
+		if err != nil {
+			return err
+		}
+		if template := c.String("event-template"); template != "" {
+			payload, err = lambda.Event(template, payload)
+			if err != nil {
+				return util.NewReadableError(err, err.Error())
+			}
+		}
+		if len(payload) == 0 {
+			payload = []byte("{}")
+		}
+
+		name := c.Positional(0)
+		if requestID := c.String("replay"); requestID != "" {
+			invocation, err := recorder.Open(p).Get(requestID)
+			if err != nil {
+				return util.NewReadableError(err, "Invocation "+requestID+" was not found. Only invocations made in `sst dev` are recorded.")
+			}
+			name = invocation.FunctionID
+			payload = invocation.Input
+		}
+		if name == "" {
+			return util.NewReadableError(nil, "Pass in the name of the function to invoke")
+		}
+
+		if url, err := server.Discover(p.PathConfig(), p.App().Stage); err == nil {
+			output, err := invokeDev(url, name, payload)
+			if err == nil {
+				fmt.Println(formatResponse(output))
+				return nil
+			}
+			if !errors.Is(err, local.ErrFunctionNotFound) {
+				return err
+			}
+		}
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return util.NewReadableError(err, "Stage \""+p.App().Stage+"\" has not been deployed")
+			}
+			return err
+		}
+		fn, err := p.LocalFunction(complete, name)
+		if err != nil {
+			return err
+		}
+		functionID := fn.Build.FunctionID
+
+		u := ui.New(c.Context)
+		defer u.Destroy()
+		events := bus.SubscribeAll()
+		printed := make(chan struct{})
+		go func() {
+			defer close(printed)
+			for evt := range events {
+				if _, ok := evt.(*invokeDoneEvent); ok {
+					return
+				}
+				u.Event(evt)
+			}
+		}()
+		var once sync.Once
+		flush := func() {
+			once.Do(func() {
+				bus.Publish(&invokeDoneEvent{})
+				<-printed
+				close(events)
+			})
+		}
+		defer flush()
+
+		build, err := p.Runtime.Build(c.Context, fn.Build)
+		if err != nil {
+			bus.Publish(&aws.FunctionBuildEvent{FunctionID: functionID, Errors: []string{err.Error()}})
+			return util.NewReadableError(nil, "")
+		}
+		bus.Publish(&aws.FunctionBuildEvent{FunctionID: functionID, Errors: build.Errors})
+		if len(build.Errors) > 0 {
+			return util.NewReadableError(nil, "")
+		}
+
+		env, err := p.LocalFunctionEnv(c.Context, complete, fn)
+		if err != nil {
+			return err
+		}
+		base := []string{}
+		for _, item := range os.Environ() {
+			// newer versions of aws-sdk do not like it when you specify both profile and credentials
+			if strings.HasPrefix(item, "AWS_PROFILE=") {
+				continue
+			}
+			base = append(base, item)
+		}
+		env = append(base, env...)
+
+		api := lambda.New()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		go http.Serve(listener, api)
+
+		workerID := "invoke-" + util.RandomString(8)
+		api.Register(workerID, functionID)
+		worker, err := p.Runtime.Run(c.Context, &runtime.RunInput{
+			CfgPath:    p.PathConfig(),
+			Runtime:    fn.Build.Runtime,
+			Server:     listener.Addr().String() + "/" + workerID,
+			FunctionID: functionID,
+			WorkerID:   workerID,
+			Build:      build,
+			Env:        env,
+		})
+		if err != nil {
+			return err
+		}
+		var logs sync.WaitGroup
+		logs.Add(1)
+		go func() {
+			defer logs.Done()
+			scanner := bufio.NewScanner(worker.Logs())
+			for scanner.Scan() {
+				bus.Publish(&aws.FunctionLogEvent{
+					FunctionID: functionID,
+					WorkerID:   workerID,
+					Line:       scanner.Text(),
+				})
+			}
+			api.Shutdown(workerID)
+		}()
+
+		requestID := lambda.NewRequestID()
+		bus.Publish(&aws.FunctionInvokedEvent{
+			FunctionID: functionID,
+			WorkerID:   workerID,
+			RequestID:  requestID,
+			Input:      payload,
+		})
+		result, err := api.Invoke(c.Context, workerID, requestID, payload, fn.Timeout)
+		worker.Stop()
+		logs.Wait()
+		if err != nil {
+			return err
+		}
+		if result.Error != nil {
+			bus.Publish(&aws.FunctionErrorEvent{
+				FunctionID:   functionID,
+				WorkerID:     workerID,
+				RequestID:    result.RequestID,
+				ErrorType:    result.Error.ErrorType,
+				ErrorMessage: result.Error.ErrorMessage,
+				Trace:        result.Error.Trace,
+			})
+			return util.NewReadableError(nil, "")
+		}
+		bus.Publish(&aws.FunctionResponseEvent{
+			FunctionID: functionID,
+			WorkerID:   workerID,
+			RequestID:  result.RequestID,
+			Output:     result.Output,
+		})
+
+		flush()
+		fmt.Println(formatResponse(result.Output))
+		return nil
+	},
+}
+
+// invokeDev invokes the function through a running `sst dev`, which prints its
+// logs. Functions that are not running in dev return ErrFunctionNotFound.
+func invokeDev(url string, functionID string, payload []byte) ([]byte, error) {
+	resp, err := http.Post(url+"/local/invoke/"+functionID, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, local.ErrFunctionNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invoke failed: %s", strings.TrimSpace(string(body)))
+	}
+	if resp.Header.Get("X-Amz-Function-Error") != "" {
+		var fnErr lambda.Error
+		json.Unmarshal(body, &fnErr)
+		return nil, util.NewReadableError(nil, fnErr.ErrorType+": "+fnErr.ErrorMessage)
+	}
+	return body, nil
+}
+
+func formatResponse(output []byte) string {
+	formatted := &bytes.Buffer{}
+	if json.Indent(formatted, output, "", "  ") != nil {
+		return string(output)
+	}
+	return formatted.String()
+}
+
+// invokeDoneEvent is published after the last event of an invocation so it is
+// printed before the response.
+type invokeDoneEvent struct{}
+
+func readPayload(path string) ([]byte, error) {
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		return io.ReadAll(os.Stdin)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not read payload "+path)
+	}
+	return data, nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
+	"github.com/sst/ion/cmd/sst/mosaic/dev"
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/server"
+)
+
+var CmdLogs = &cli.Command{
+	Name: "logs",
+	Description: cli.Description{
+		Short: "Search the invocations of your functions in dev",
+		Long: strings.Join([]string{
+			"Prints the invocations of your functions that were recorded by `sst dev`, along with their logs.",
+			"",
+			"```bash frame=\"none\"",
+			"sst logs",
+			"```",
+			"",
+			"Narrow them down to a function, a time range, the ones that failed, or the ones that match a pattern. The pattern is a regular expression that's matched against the logs, the input, the output, and the error.",
+			"",
+			"```bash frame=\"none\"",
+			"sst logs --function MyFunction --since 1h --errors --grep \"user_[0-9]+\"",
+			"```",
+			"",
+			"Use `--follow` to keep printing invocations as they happen while `sst dev` is running.",
+			"",
+			"Invocations are kept for 7 days, and up to 1000 per function. Set `SST_LOG_RETENTION` in hours to keep them for longer.",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "function",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show the invocations of a function",
+				Long:  "Only show the invocations of the function with this name.",
+			},
+		},
+		{
+			Name: "since",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show invocations since a time",
+				Long:  "Only show invocations since a duration ago, like `30m`, `2h`, or `1d`, or since a date, like `2024-06-01T12:00:00Z`.",
+			},
+		},
+		{
+			Name: "grep",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show invocations that match a pattern",
+				Long:  "Only show invocations where the logs, input, output, or error match a regular expression.",
+			},
+		},
+		{
+			Name: "errors",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Only show invocations that failed",
+				Long:  "Only show invocations that failed.",
+			},
+		},
+		{
+			Name: "follow",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Keep printing new invocations",
+				Long:  "Keep printing new invocations as they happen. This needs `sst dev` to be running.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		filter := &logFilter{
+			function: c.String("function"),
+			errors:   c.Bool("errors"),
+		}
+		if since := c.String("since"); since != "" {
+			filter.since, err = parseSince(since, time.Now())
+			if err != nil {
+				return util.NewReadableError(err, "Invalid time \""+since+"\". Use a duration like 30m, 2h, or 1d, or a date like 2024-06-01T12:00:00Z.")
+			}
+		}
+		if pattern := c.String("grep"); pattern != "" {
+			filter.grep, err = regexp.Compile(pattern)
+			if err != nil {
+				return util.NewReadableError(err, "Invalid pattern \""+pattern+"\": "+err.Error())
+			}
+		}
+
+		var url string
+		if c.Bool("follow") {
+			url, err = server.Discover(p.PathConfig(), p.App().Stage)
+			if err != nil {
+				return util.NewReadableError(err, "To follow invocations, start `sst dev` for stage \""+p.App().Stage+"\"")
+			}
+		}
+
+		invocations, err := recorder.Open(p).List(filter.function)
+		if err != nil {
+			return err
+		}
+		slices.Reverse(invocations)
+		for _, invocation := range invocations {
+			if filter.match(invocation) {
+				printInvocation(invocation)
+			}
+		}
+
+		if url == "" {
+			return nil
+		}
+		evts, err := dev.Stream(c.Context, url,
+			aws.FunctionInvokedEvent{},
+			aws.FunctionLogEvent{},
+			aws.FunctionResponseEvent{},
+			aws.FunctionErrorEvent{},
+		)
+		if err != nil {
+			return err
+		}
+		collector := recorder.Collect(func(invocation *recorder.Invocation) error {
+			if filter.match(invocation) {
+				printInvocation(invocation)
+			}
+			return nil
+		})
+		for evt := range evts {
+			collector.Event(evt)
+		}
+		return nil
+	},
+}
+
+type logFilter struct {
+	function string
+	since    time.Time
+	grep     *regexp.Regexp
+	errors   bool
+}
+
+func (f *logFilter) match(invocation *recorder.Invocation) bool {
+	if f.function != "" && invocation.FunctionID != f.function {
+		return false
+	}
+	if !f.since.IsZero() && invocation.Start.Before(f.since) {
+		return false
+	}
+	if f.errors && invocation.Error == nil {
+		return false
+	}
+	if f.grep == nil {
+		return true
+	}
+	if f.grep.Match(invocation.Input) || f.grep.Match(invocation.Output) {
+		return true
+	}
+	for _, log := range invocation.Logs {
+		if f.grep.MatchString(log.Line) {
+			return true
+		}
+	}
+	if invocation.Error != nil {
+		return f.grep.MatchString(invocation.Error.ErrorType + ": " + invocation.Error.ErrorMessage)
+	}
+	return false
+}
+
+// parseSince reads a duration before now, like 30m or 1d, or a date.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		parsed, err := strconv.Atoi(days)
+		if err == nil {
+			return now.Add(-time.Duration(parsed) * 24 * time.Hour), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, time.Local)
+}
+
+func printInvocation(invocation *recorder.Invocation) {
+	status := ui.TEXT_SUCCESS.Render("Done")
+	if invocation.Error != nil {
+		status = ui.TEXT_DANGER.Render("Error")
+	}
+	fmt.Println(
+		ui.TEXT_DIM.Render(invocation.Start.Local().Format(time.DateTime)),
+		ui.TEXT_NORMAL_BOLD.Render(invocation.FunctionID),
+		status,
+		ui.TEXT_DIM.Render(fmt.Sprintf("took %v", invocation.End.Sub(invocation.Start).Round(time.Millisecond))),
+		ui.TEXT_DIM.Render(invocation.RequestID),
+	)
+	for _, log := range invocation.Logs {
+		offset := fmt.Sprintf("%.9s", fmt.Sprintf("+%v", log.Timestamp.Sub(invocation.Start).Round(time.Millisecond)))
+		fmt.Println("  " + ui.TEXT_DIM.Render(fmt.Sprintf("%-11s", offset)) + " " + log.Line)
+	}
+	if invocation.Error != nil {
+		fmt.Println("  " + ui.TEXT_DANGER.Render(invocation.Error.ErrorType+": "+invocation.Error.ErrorMessage))
+		for _, item := range invocation.Error.Trace {
+			if strings.Contains(item, "Error:") {
+				continue
+			}
+			fmt.Println("  " + ui.TEXT_DIM.Render("↳ "+strings.TrimSpace(item)))
+		}
+	}
+	fmt.Println()
+}
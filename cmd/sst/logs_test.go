@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
+	"github.com/sst/ion/pkg/runtime/lambda"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"30m":                  now.Add(-30 * time.Minute),
+		"2d":                   now.Add(-48 * time.Hour),
+		"2024-06-01T08:00:00Z": time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := parseSince(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%v: expected %v, got %v %v", value, want, got, err)
+		}
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	now := time.Now()
+	ok := &recorder.Invocation{
+		FunctionID: "Api",
+		Start:      now.Add(-time.Hour),
+		Input:      json.RawMessage(`{"user":"user_42"}`),
+		Logs:       []recorder.Log{{Line: "handled"}},
+	}
+	failed := &recorder.Invocation{
+		FunctionID: "Consumer",
+		Start:      now,
+		Error:      &lambda.Error{ErrorType: "TypeError", ErrorMessage: "boom"},
+	}
+	tests := []struct {
+		filter *logFilter
+		ok     bool
+		failed bool
+	}{
+		{&logFilter{}, true, true},
+		{&logFilter{function: "Api"}, true, false},
+		{&logFilter{errors: true}, false, true},
+		{&logFilter{since: now.Add(-time.Minute)}, false, true},
+		{&logFilter{grep: regexp.MustCompile(`user_\d+`)}, true, false},
+		{&logFilter{grep: regexp.MustCompile(`handled|TypeError`)}, true, true},
+	}
+	for index, test := range tests {
+		if test.filter.match(ok) != test.ok || test.filter.match(failed) != test.failed {
+			t.Fatalf("test %v: expected %v and %v", index, test.ok, test.failed)
+		}
+	}
+}
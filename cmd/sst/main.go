@@ -337,6 +337,22 @@ var root = &cli.Command{
 						Long:  "Filter events.",
 					},
 				},
+				{
+					Name: "replay",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Replay an event log",
+						Long:  "Replay an event log, like `.sst/log/event.log`, instead of connecting to `sst dev`.",
+					},
+				},
+				{
+					Name: "speed",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Speed up the replay",
+						Long:  "How much faster to replay the event log. Use `0` to replay it instantly.",
+					},
+				},
 			},
 		},
 		{
@@ -381,6 +397,15 @@ var root = &cli.Command{
 					"deployed by `sst dev`.",
 					":::",
 					"",
+					"Files that are ignored by your `.gitignore` files are not watched, along with",
+					"`node_modules` and hidden directories. To ignore other files, set `SST_WATCH_IGNORE` to a",
+					"comma separated list of globs. Changes made together, like saving all your files or",
+					"switching branches, are picked up at once.",
+					"",
+					"```bash frame=\"none\"",
+					"SST_WATCH_IGNORE=\"*.generated.ts,fixtures/\" sst dev",
+					"```",
+					"",
 					"Optionally, you can disable the multiplexer and run `sst dev` in basic mode.",
 					"",
 					"```bash frame=\"none\"",
@@ -400,11 +425,74 @@ var root = &cli.Command{
 					"By wrapping your command, it'll load your [linked resources](/docs/linking) in the",
 					"environment.",
 					"",
+					"If the command assumes an AWS role, it gets its credentials from the dev server through",
+					"`AWS_CONTAINER_CREDENTIALS_FULL_URI`, the same way it would in a container. The AWS SDK",
+					"refreshes them as they expire, so the command keeps running.",
+					"",
 					"To pass in a flag to the command, use `--`.",
 					"",
 					"```bash frame=\"none\"",
 					"sst dev -- next dev --turbo",
 					"```",
+					"",
+					"Your functions can also be run locally without going through AWS. The dev server",
+					"implements the Lambda Runtime API and lets you invoke a function by its name, either",
+					"with [`sst invoke`](#invoke) or over HTTP.",
+					"",
+					"```bash frame=\"none\"",
+					"curl -X POST http://localhost:13557/local/invoke/MyFunction -d '{}'",
+					"curl http://localhost:13557/local/url/MyFunction/users",
+					"```",
+					"",
+					"The `/local/url` endpoint passes the request in as a function URL event.",
+					"",
+					"Every invocation is recorded in `.sst/invocations`. You can search them with [`sst logs`](#logs),",
+					"or replay one against your current code from the console, with [`sst invoke --replay`](#invoke),",
+					"or over HTTP.",
+					"",
+					"```bash frame=\"none\"",
+					"curl -X POST http://localhost:13557/local/replay/<requestID>",
+					"```",
+					"",
+					"Live functions connect to your machine through AWS IoT. If that's not available in your",
+					"account, you can set `SST_LIVE_RELAY` to the URL of a WebSocket relay instead. The dev",
+					"server runs one at `/relay` that you can expose through a tunnel.",
+					"",
+					"```bash frame=\"none\"",
+					"SST_LIVE_RELAY=wss://my-tunnel.example.com/relay sst dev",
+					"```",
+					"",
+					"Clients need a token to connect. If `SST_LIVE_RELAY_TOKEN` isn't set, one is generated",
+					"and kept in the `.sst/` directory.",
+					"",
+					"Functions need to be redeployed by `sst dev` to pick up the relay.",
+					"",
+					"Invocations of a function share a pool of local workers, up to 4 at a time by default.",
+					"Others wait for a worker to free up. Workers that haven't been used for 5 minutes are",
+					"stopped. You can change these with `SST_DEV_CONCURRENCY` and `SST_DEV_IDLE_TIMEOUT`, in",
+					"seconds. The Functions pane shows how many workers are active.",
+					"",
+					"```bash frame=\"none\"",
+					"SST_DEV_CONCURRENCY=10 SST_DEV_IDLE_TIMEOUT=60 sst dev",
+					"```",
+					"",
+					"Like in Lambda, an invocation fails if it runs past the function's timeout and the worker",
+					"is restarted. On Linux, workers are also started with `prlimit` to limit them to the",
+					"function's memory and fail with `Runtime.OutOfMemory` if they run out. Set",
+					"`SST_DEV_NO_MEMORY_LIMIT` to turn this off.",
+					"",
+					"To step through your functions in a debugger, use `--inspect`. Node functions are started",
+					"with `--inspect` and Python functions with `debugpy`. Each function listens on its own port",
+					"that stays the same across restarts, and the address is shown in the Functions pane.",
+					"",
+					"```bash frame=\"none\"",
+					"sst dev --inspect",
+					"sst dev --inspect=MyFunction,MyOtherFunction",
+					"```",
+					"",
+					"While inspecting, the functions are deployed with a 15 minute timeout so they don't time",
+					"out while paused on a breakpoint. Services in front of them, like API Gateway, still have",
+					"their own limits.",
 				}, "\n"),
 			},
 			Flags: []cli.Flag{
@@ -416,6 +504,15 @@ var root = &cli.Command{
 						Long:  "Defaults to using the multiplexer or `mosaic` mode. Use `basic` to turn it off.",
 					},
 				},
+				{
+					Name:    "inspect",
+					Type:    "string",
+					NoValue: "*",
+					Description: cli.Description{
+						Short: "Start functions with a debugger",
+						Long:  "Start functions with a debugger listening. Pass in a comma separated list of function names to only inspect those, like `--inspect=MyFunction`.",
+					},
+				},
 			},
 			Args: []cli.Argument{
 				{
@@ -481,6 +578,34 @@ var root = &cli.Command{
 					"```bash frame=\"none\"",
 					"sst deploy --target urn:pulumi:prod::www::sst:aws:Astro::Astro,urn:pulumi:prod::www::sst:aws:Bucket::Assets",
 					"```",
+					"",
+					"Optionally, mark the stage as ephemeral by passing in how long it should live for.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --stage pr-123 --ttl 72h",
+					"```",
+					"",
+					"Every deploy with `--ttl` pushes the expiry back, and a deploy without it keeps the stage for good. Expired stages are removed with [`sst stage gc`](#stage-gc).",
+					"",
+					"To find out why a deploy is slow, profile it.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --profile",
+					"```",
+					"",
+					"This prints the slowest resources and the chain of dependent resources that took the longest. It also writes a `profile.chrome.json` that can be opened in [Perfetto](https://ui.perfetto.dev) and a `profile.otlp.json` to the `.sst/log/` directory.",
+					"",
+					"If the stage is protected in your `sst.config.ts`, you'll be asked to type in the name of the stage before any resources are deleted or replaced. In CI, this fails unless you pass in `--allow-destroy`.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --stage production --allow-destroy",
+					"```",
+					"",
+					"To review the changes before they are made, pass in `--confirm`. This runs a diff first and asks you to confirm it. The deploy then makes exactly those changes, and fails if anything changed in the meantime.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --stage production --confirm",
+					"```",
 				}, "\n"),
 			},
 			Flags: []cli.Flag{
@@ -491,6 +616,46 @@ var root = &cli.Command{
 						Long:  "Comma separated list of target URNs.",
 					},
 				},
+				{
+					Name: "ttl",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Remove the stage after this long, like 72h",
+						Long:  "Mark the stage as expiring after the given duration, like `72h`. Expired stages are removed by `sst stage gc`. Deploying without it clears the expiry.",
+					},
+				},
+				{
+					Name: "profile",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Print how long each resource took",
+						Long:  "Print the slowest resources and the critical path of the deploy. It also exports a Chrome trace and an OTLP trace to the `.sst/log/` directory.",
+					},
+				},
+				{
+					Name: "confirm",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Review the changes before deploying them",
+						Long:  "Preview the changes first and ask to confirm them before deploying. The deploy then makes exactly the changes that were confirmed.",
+					},
+				},
+				{
+					Name: "yes",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Skip the prompt with --confirm",
+						Long:  "Skip the prompt when used with `--confirm`. The changes are still printed and the deploy still follows them.",
+					},
+				},
+				{
+					Name: "allow-destroy",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Allow deletes and replacements on a protected stage",
+						Long:  "Allow resources to be deleted or replaced on a protected stage without a prompt. Resources that are always protected still can't be.",
+					},
+				},
 			},
 			Examples: []cli.Example{
 				{
@@ -768,6 +933,27 @@ var root = &cli.Command{
 				CmdSecretList,
 			},
 		},
+		{
+			Name: "stage",
+			Description: cli.Description{
+				Short: "Manage the stages of your app",
+				Long:  "Manage the stages of your app.",
+			},
+			Children: []*cli.Command{
+				CmdStageList,
+				CmdStageGC,
+			},
+		},
+		{
+			Name: "tags",
+			Description: cli.Description{
+				Short: "Manage the tags on your resources",
+				Long:  "Manage the tags on your resources.",
+			},
+			Children: []*cli.Command{
+				CmdTagsReport,
+			},
+		},
 		{
 			Name: "shell",
 			Args: []cli.Argument{
@@ -869,6 +1055,8 @@ var root = &cli.Command{
 					"```bash frame=\"none\"",
 					"sst remove --target urn:pulumi:prod::www::sst:aws:Astro::Astro,urn:pulumi:prod::www::sst:aws:Bucket::Assets",
 					"```",
+					"",
+					"If the stage is protected in your `sst.config.ts`, you'll be asked to type in the name of the stage first. In CI, this fails unless you pass in `--allow-destroy`.",
 				}, "\n"),
 			},
 			Flags: []cli.Flag{
@@ -880,6 +1068,14 @@ var root = &cli.Command{
 						Long:  "Comma separated list of target URNs.",
 					},
 				},
+				{
+					Name: "allow-destroy",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Allow removing a protected stage",
+						Long:  "Allow a protected stage to be removed without a prompt. Resources that are always protected still can't be.",
+					},
+				},
 			},
 			Run: CmdRemove,
 		},
@@ -987,18 +1183,7 @@ var root = &cli.Command{
 				return nil
 			},
 		},
-		{
-			Name:   "common-errors",
-			Hidden: true,
-			Run: func(cli *cli.Cli) error {
-				data, err := json.MarshalIndent(project.CommonErrors, "", "  ")
-				if err != nil {
-					return err
-				}
-				fmt.Println(string(data))
-				return nil
-			},
-		},
+		CmdCommonErrors,
 		{
 			Name: "refresh",
 			Description: cli.Description{
@@ -1098,5 +1283,11 @@ var root = &cli.Command{
 		CmdCert,
 		CmdTunnel,
 		CmdDiagnostic,
+		CmdOutput,
+		CmdGraph,
+		CmdOrphans,
+		CmdCost,
+		CmdInvoke,
+		CmdLogs,
 	},
 }
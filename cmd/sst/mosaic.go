@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/kballard/go-shellquote"
 	"github.com/sst/ion/cmd/sst/cli"
@@ -16,11 +15,14 @@ import (
 	"github.com/sst/ion/cmd/sst/mosaic/cloudflare"
 	"github.com/sst/ion/cmd/sst/mosaic/deployer"
 	"github.com/sst/ion/cmd/sst/mosaic/dev"
+	"github.com/sst/ion/cmd/sst/mosaic/local"
 	"github.com/sst/ion/cmd/sst/mosaic/multiplexer"
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
 	"github.com/sst/ion/cmd/sst/mosaic/socket"
 	"github.com/sst/ion/cmd/sst/mosaic/watcher"
 	"github.com/sst/ion/internal/util"
 	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
 	"github.com/sst/ion/pkg/project"
 	"github.com/sst/ion/pkg/runtime"
 	"github.com/sst/ion/pkg/server"
@@ -66,7 +68,6 @@ func CmdMosaic(c *cli.Cli) error {
 		var cmd *exec.Cmd
 		env := map[string]string{}
 		processExited := make(chan bool)
-		timeout := time.Hour * 24
 		for {
 			select {
 			case <-c.Context.Done():
@@ -74,13 +75,6 @@ func CmdMosaic(c *cli.Cli) error {
 			case <-processExited:
 				c.Cancel()
 				continue
-			case <-time.After(timeout):
-				env = map[string]string{}
-				go func() {
-					evts <- true
-				}()
-				fmt.Println("[timeout]")
-				continue
 			case _, ok := <-evts:
 				if !ok {
 					return nil
@@ -93,9 +87,6 @@ func CmdMosaic(c *cli.Cli) error {
 				if err != nil {
 					return err
 				}
-				if _, ok := nextEnv["AWS_ACCESS_KEY_ID"]; ok {
-					timeout = time.Minute * 45
-				}
 				if diff(env, nextEnv) {
 					if cmd != nil {
 						cmd.Process.Signal(syscall.SIGINT)
@@ -133,6 +124,24 @@ func CmdMosaic(c *cli.Cli) error {
 		return err
 	}
 	os.Setenv("SST_STAGE", p.App().Stage)
+	if inspect := c.String("inspect"); inspect != "" {
+		functions := []string{}
+		if inspect != "*" {
+			functions = strings.Split(inspect, ",")
+		}
+		p.Runtime.Inspect(functions...)
+		// picked up by the function component to relax the timeout
+		os.Setenv("SST_INSPECT", inspect)
+	}
+	if flag.SST_LIVE_RELAY != "" {
+		token, err := aws.RelayToken(p)
+		if err != nil {
+			return err
+		}
+		// picked up by the function component so the functions can connect
+		flag.SST_LIVE_RELAY_TOKEN = token
+		os.Setenv("SST_LIVE_RELAY_TOKEN", token)
+	}
 	slog.Info("mosaic", "project", p.PathRoot())
 
 	wg.Go(func() error {
@@ -155,6 +164,18 @@ func CmdMosaic(c *cli.Cli) error {
 		return socket.Start(c.Context, p, server)
 	})
 
+	functions := local.Setup(c.Context, p, server)
+	aws.SetupRelay(server)
+	wg.Go(func() error {
+		defer c.Cancel()
+		return functions.Start(c.Context, p)
+	})
+
+	wg.Go(func() error {
+		defer c.Cancel()
+		return recorder.Start(c.Context, p)
+	})
+
 	wg.Go(func() error {
 		evts := bus.Subscribe(&runtime.BuildInput{})
 		for {
@@ -177,7 +198,7 @@ func CmdMosaic(c *cli.Cli) error {
 		case "aws":
 			wg.Go(func() error {
 				defer c.Cancel()
-				return aws.Start(c.Context, p, server, args.(map[string]interface{}))
+				return aws.Start(c.Context, p, server, functions, args.(map[string]interface{}))
 			})
 		case "cloudflare":
 			wg.Go(func() error {
@@ -1,18 +1,16 @@
 package aws
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/url"
+	"net/http/httputil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,17 +18,15 @@ import (
 	"sync"
 	"time"
 
-	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
-	"github.com/aws/aws-sdk-go-v2/service/iot"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/sst/ion/cmd/sst/mosaic/aws/iot_writer"
-	"github.com/sst/ion/cmd/sst/mosaic/watcher"
+	"github.com/sst/ion/cmd/sst/mosaic/aws/transport"
 	"github.com/sst/ion/internal/util"
-	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
 	"github.com/sst/ion/pkg/project"
 	"github.com/sst/ion/pkg/project/provider"
-	"github.com/sst/ion/pkg/runtime"
+	"github.com/sst/ion/pkg/runtime/lambda"
 	"github.com/sst/ion/pkg/server"
 )
 
@@ -76,27 +72,35 @@ type FunctionLogEvent struct {
 	Line       string
 }
 
+// FunctionWorkersEvent is published when the local workers of a function
+// change, with how many are handling an invocation, how many are idle and how
+// many invocations are waiting for one.
+type FunctionWorkersEvent struct {
+	FunctionID string
+	Active     int
+	Idle       int
+	Queued     int
+	Max        int
+}
+
+// Pool runs the invocations of live functions on local workers.
+type Pool interface {
+	Ready(functionID string) bool
+	SetEnv(functionID string, env []string)
+	Send(ctx context.Context, functionID string, invocation *lambda.Invocation) (*lambda.Result, error)
+}
+
 var ErrIoTDelay = fmt.Errorf("iot not available")
 
 func Start(
 	ctx context.Context,
 	p *project.Project,
 	s *server.Server,
+	pool Pool,
 	args map[string]interface{},
 ) error {
-
-	expire := time.Hour * 24
-	from := time.Now()
-	server := fmt.Sprintf("localhost:%d/lambda/", s.Port)
-
 	prov, _ := p.Provider("aws")
 	config := prov.(*provider.AwsProvider).Config()
-	slog.Info("getting endpoint")
-	iotClient := iot.NewFromConfig(config)
-	endpointResp, err := iotClient.DescribeEndpoint(ctx, &iot.DescribeEndpointInput{})
-	if err != nil {
-		return err
-	}
 
 	bootstrapData, err := provider.AwsBootstrap(config)
 	if err != nil {
@@ -104,76 +108,49 @@ func Start(
 	}
 
 	s3Client := s3.NewFromConfig(config)
-
-	originalURL, err := url.Parse(fmt.Sprintf("wss://%s/mqtt?X-Amz-Expires=%s", *endpointResp.EndpointAddress, strconv.FormatInt(int64(expire/time.Second), 10)))
-	if err != nil {
-		return err
+	if flag.SST_LIVE_RELAY != "" {
+		// the relay has no message size limit so responses don't go through s3
+		s3Client = nil
 	}
-	slog.Info("found endpoint endpoint", "url", originalURL.String())
 
-	creds, err := config.Credentials.Retrieve(ctx)
+	client, err := connect(ctx, config)
 	if err != nil {
 		return err
 	}
-	sessionToken := creds.SessionToken
-	creds.SessionToken = ""
-
-	signer := v4.NewSigner()
-	req := &http.Request{
-		Method: "GET",
-		URL:    originalURL,
-	}
+	prefix := fmt.Sprintf("ion/%s/%s", p.App().Name, p.App().Stage)
+	return run(ctx, s, pool, client, s3Client, bootstrapData.Asset, prefix)
+}
 
-	presignedURL, _, err := signer.PresignHTTP(ctx, creds, req, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "iotdevicegateway", config.Region, from)
-	if err != nil {
-		return err
-	}
-	slog.Info("signed request", "url", presignedURL)
-	if sessionToken != "" {
-		presignedURL += "&X-Amz-Security-Token=" + url.QueryEscape(sessionToken)
+// SetupRelay serves the websocket relay on the dev server in relay mode, so it
+// can be exposed through a tunnel. It has to be registered before the server
+// starts since Start connects to it.
+func SetupRelay(s *server.Server) {
+	if flag.SST_LIVE_RELAY == "" {
+		return
 	}
+	s.Mux.Handle("/relay", transport.NewRelayServer(flag.SST_LIVE_RELAY_TOKEN))
+}
 
-	opts := MQTT.
-		NewClientOptions().
-		AddBroker(presignedURL).
-		SetClientID(
-			hex.EncodeToString(func(b []byte) []byte { _, _ = rand.Read(b); return b }(make([]byte, 16))),
-		).
-		SetTLSConfig(&tls.Config{
-			InsecureSkipVerify: true,
-		}).
-		SetWebsocketOptions(&MQTT.WebsocketOptions{
-			ReadBufferSize:  1024 * 1000,
-			WriteBufferSize: 1024 * 1000,
-		}).
-		SetCleanSession(false).
-		SetAutoReconnect(true).
-		SetMaxReconnectInterval(time.Second * 1).
-		SetConnectionLostHandler(func(c MQTT.Client, err error) {
-			slog.Info("mqtt connection lost", "error", err)
-		}).
-		SetReconnectingHandler(func(c MQTT.Client, co *MQTT.ClientOptions) {
-			slog.Info("mqtt reconnecting")
-		}).
-		SetOnConnectHandler(func(c MQTT.Client) {
-			slog.Info("mqtt connected")
-		}).
-		SetKeepAlive(time.Second * 1200).
-		SetPingTimeout(time.Second * 60)
-
-	mqttClient := MQTT.NewClient(opts)
-	if token := mqttClient.Connect(); token.Wait() && token.Error() != nil {
-		return fmt.Errorf("failed to connect to mqtt: %w", token.Error())
-	}
+// run forwards the Runtime API requests of the live functions under the prefix
+// to the dev server, and runs their invocations on the pool.
+func run(
+	ctx context.Context,
+	s *server.Server,
+	pool Pool,
+	client transport.Transport,
+	s3Client *s3.Client,
+	bucket string,
+	prefix string,
+) error {
+	server := fmt.Sprintf("localhost:%d/lambda/", s.Port)
 
 	var pending sync.Map
-	initChan := make(chan MQTT.Message, 1000)
-	shutdownChan := make(chan MQTT.Message, 1000)
+	initChan := make(chan transport.Message, 1000)
+	shutdownChan := make(chan transport.Message, 1000)
 
-	prefix := fmt.Sprintf("ion/%s/%s", p.App().Name, p.App().Stage)
 	reader := iot_writer.NewReader(s3Client)
-	if token := mqttClient.Subscribe(prefix+"/+/response/#", 1, func(c MQTT.Client, m MQTT.Message) {
-		slog.Info("iot", "topic", m.Topic(), "payload", len(m.Payload()))
+	if err := client.Subscribe(prefix+"/+/response/#", func(m transport.Message) {
+		slog.Info("iot", "topic", m.Topic, "payload", len(m.Payload))
 		for _, msg := range reader.Read(m) {
 			slog.Info("read", "requestID", msg.ID, "data", len(msg.Data))
 			write, ok := pending.Load(msg.ID)
@@ -188,273 +165,113 @@ func Start(
 			}
 			casted.Write(msg.Data)
 		}
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
-	}
-
-	type WorkerInfo struct {
-		FunctionID       string
-		WorkerID         string
-		Worker           runtime.Worker
-		CurrentRequestID string
-		Env              []string
-	}
-
-	type workerResponse struct {
-		response    *http.Response
-		requestBody *bytes.Buffer
-		workerID    string
-		path        []string
+	}); err != nil {
+		return err
 	}
-	workerResponseChan := make(chan workerResponse, 1000)
-	workerShutdownChan := make(chan *WorkerInfo, 1000)
 
-	evts := bus.Subscribe(&watcher.FileChangedEvent{}, &project.CompleteEvent{}, &runtime.BuildInput{})
-
-	if token := mqttClient.Subscribe(prefix+"/+/init", 1, func(c MQTT.Client, m MQTT.Message) {
-		slog.Info("iot", "topic", m.Topic())
+	if err := client.Subscribe(prefix+"/+/init", func(m transport.Message) {
+		slog.Info("iot", "topic", m.Topic)
 		initChan <- m
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
-	if token := mqttClient.Subscribe(prefix+"/+/shutdown", 1, func(c MQTT.Client, m MQTT.Message) {
-		slog.Info("iot", "topic", m.Topic())
+	if err := client.Subscribe(prefix+"/+/shutdown", func(m transport.Message) {
+		slog.Info("iot", "topic", m.Topic)
 		shutdownChan <- m
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
 	slog.Info("connected to iot")
 
-	go func() {
-		evts := bus.Subscribe(&FunctionLogEvent{}, &FunctionInvokedEvent{}, &FunctionResponseEvent{}, &FunctionErrorEvent{}, &FunctionBuildEvent{})
-		logs := map[string]*os.File{}
-
-		getLog := func(functionID string, requestID string) *os.File {
-			log, ok := logs[requestID]
-			if !ok {
-				path := p.PathLog(fmt.Sprintf("lambda/%s/%d-%s", functionID, time.Now().Unix(), requestID))
-				os.MkdirAll(filepath.Dir(path), 0755)
-				log, _ = os.Create(path)
-				logs[requestID] = log
-			}
-			return log
-		}
-
-		for range evts {
-			for evt := range evts {
-				switch evt := evt.(type) {
-				case *FunctionInvokedEvent:
-					log := getLog(evt.FunctionID, evt.RequestID)
-					log.WriteString("invocation " + evt.RequestID + "\n")
-					log.WriteString(string(evt.Input))
-					log.WriteString("\n")
-				case *FunctionLogEvent:
-					getLog(evt.FunctionID, evt.RequestID).WriteString(evt.Line + "\n")
-				case *FunctionResponseEvent:
-					log := getLog(evt.FunctionID, evt.RequestID)
-					log.WriteString("response " + evt.RequestID + "\n")
-					log.WriteString(string(evt.Output))
-					log.WriteString("\n")
-					delete(logs, evt.RequestID)
-				case *FunctionErrorEvent:
-					getLog(evt.FunctionID, evt.RequestID).WriteString(evt.ErrorType + ": " + evt.ErrorMessage + "\n")
-					delete(logs, evt.RequestID)
+	// serve takes the invocations of a remote execution environment and runs
+	// them on the local workers of the function until the environment shuts
+	// down. Environments don't get a local process of their own, so the number
+	// of processes is bounded by the pool and not by how many environments AWS
+	// spins up.
+	serve := func(ctx context.Context, workerID string, functionID string) {
+		base := "http://" + server + workerID + "/runtime/invocation/"
+		for {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"next", nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
 				}
+				slog.Error("failed to get next invocation", "workerID", workerID, "error", err)
+				time.Sleep(time.Second)
+				continue
 			}
-		}
-	}()
-
-	go func() {
-		workers := map[string]*WorkerInfo{}
-		workerEnv := map[string][]string{}
-		builds := map[string]*runtime.BuildOutput{}
-		targets := map[string]*runtime.BuildInput{}
-
-		getBuildOutput := func(functionID string) *runtime.BuildOutput {
-			build := builds[functionID]
-			if build != nil {
-				return build
+			payload, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil || resp.StatusCode != http.StatusOK {
+				slog.Error("failed to get next invocation", "workerID", workerID, "status", resp.StatusCode, "error", err)
+				time.Sleep(time.Second)
+				continue
 			}
-			target, _ := targets[functionID]
-			build, err = p.Runtime.Build(ctx, target)
-			if err == nil {
-				bus.Publish(&FunctionBuildEvent{
-					FunctionID: functionID,
-					Errors:     build.Errors,
-				})
-			} else {
-				bus.Publish(&FunctionBuildEvent{
-					FunctionID: functionID,
-					Errors:     []string{err.Error()},
-				})
+			topic := prefix + "/" + workerID + "/ack"
+			slog.Info("acking", "topic", topic)
+			client.Publish(topic, []byte{1})
+
+			invocation := &lambda.Invocation{
+				RequestID: resp.Header.Get("Lambda-Runtime-Aws-Request-Id"),
+				Payload:   payload,
+				Headers:   http.Header{},
 			}
-			if err != nil || len(build.Errors) > 0 {
-				delete(builds, functionID)
-				return nil
+			if deadline, err := strconv.ParseInt(resp.Header.Get("Lambda-Runtime-Deadline-Ms"), 10, 64); err == nil {
+				invocation.Deadline = time.UnixMilli(deadline)
 			}
-			builds[functionID] = build
-			return build
-		}
-
-		run := func(functionID string, workerID string) bool {
-			build := getBuildOutput(functionID)
-			if build == nil {
-				return false
-			}
-			target, ok := targets[functionID]
-			if !ok {
-				return false
+			for _, name := range []string{
+				"Lambda-Runtime-Invoked-Function-Arn",
+				"Lambda-Runtime-Trace-Id",
+				"Lambda-Runtime-Client-Context",
+				"Lambda-Runtime-Cognito-Identity",
+			} {
+				if value := resp.Header.Get(name); value != "" {
+					invocation.Headers.Set(name, value)
+				}
 			}
-			worker, err := p.Runtime.Run(ctx, &runtime.RunInput{
-				CfgPath:    p.PathConfig(),
-				Runtime:    target.Runtime,
-				Server:     server + workerID,
-				WorkerID:   workerID,
-				FunctionID: functionID,
-				Build:      build,
-				Env:        workerEnv[workerID],
-			})
+			result, err := pool.Send(ctx, functionID, invocation)
 			if err != nil {
-				slog.Error("failed to run worker", "error", err)
-				return false
+				if ctx.Err() != nil {
+					return
+				}
+				result = &lambda.Result{
+					RequestID: invocation.RequestID,
+					Error: &lambda.Error{
+						ErrorType:    "Runtime.Unknown",
+						ErrorMessage: err.Error(),
+					},
+				}
 			}
-			info := &WorkerInfo{
-				FunctionID: functionID,
-				Worker:     worker,
-				WorkerID:   workerID,
+			if err := respond(ctx, base+invocation.RequestID, result); err != nil {
+				slog.Error("failed to send response", "workerID", workerID, "requestID", invocation.RequestID, "error", err)
 			}
-			go func() {
-				logs := worker.Logs()
-				scanner := bufio.NewScanner(logs)
-				for scanner.Scan() {
-					line := scanner.Text()
-					bus.Publish(&FunctionLogEvent{
-						FunctionID: functionID,
-						WorkerID:   workerID,
-						RequestID:  info.CurrentRequestID,
-						Line:       line,
-					})
-				}
-				workerShutdownChan <- info
-			}()
-			workers[workerID] = info
-
-			return true
 		}
+	}
 
+	go func() {
+		serving := map[string]context.CancelFunc{}
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case evt := <-workerResponseChan:
-				info, ok := workers[evt.workerID]
-				if !ok {
-					continue
-				}
-				responseBody, err := io.ReadAll(evt.response.Body)
-				if err != nil {
-					continue
-				}
-				if evt.path[len(evt.path)-1] == "next" {
-					info.CurrentRequestID = evt.response.Header.Get("lambda-runtime-aws-request-id")
-					bus.Publish(&FunctionInvokedEvent{
-						FunctionID: info.FunctionID,
-						WorkerID:   info.WorkerID,
-						RequestID:  info.CurrentRequestID,
-						Input:      responseBody,
-					})
-					topic := prefix + "/" + info.WorkerID + "/ack"
-					slog.Info("acking", "topic", topic)
-					mqttClient.Publish(topic, 1, false, []byte{1}).Wait()
-				}
-				if evt.path[len(evt.path)-1] == "response" {
-					bus.Publish(&FunctionResponseEvent{
-						FunctionID: info.FunctionID,
-						WorkerID:   info.WorkerID,
-						RequestID:  evt.path[len(evt.path)-2],
-						Output:     responseBody,
-					})
-				}
-				if evt.path[len(evt.path)-1] == "error" {
-					fee := &FunctionErrorEvent{
-						FunctionID: info.FunctionID,
-						WorkerID:   info.WorkerID,
-						RequestID:  evt.path[len(evt.path)-2],
-					}
-					json.Unmarshal(evt.requestBody.Bytes(), &fee)
-					bus.Publish(fee)
-				}
-			case info := <-workerShutdownChan:
-				slog.Info("worker died", "workerID", info.WorkerID)
-				existing, ok := workers[info.WorkerID]
-				if !ok {
-					continue
-				}
-				// only delete if a new worker hasn't already been started
-				if existing == info {
-					slog.Info("deleting worker", "workerID", info.WorkerID)
-					delete(workers, info.WorkerID)
-				}
-				break
-			case unknown := <-evts:
-				switch evt := unknown.(type) {
-				case *runtime.BuildInput:
-					targets[evt.FunctionID] = evt
-				case *watcher.FileChangedEvent:
-					slog.Info("checking if code needs to be rebuilt", "file", evt.Path)
-					toBuild := map[string]bool{}
-
-					for functionID := range builds {
-						target, ok := targets[functionID]
-						if !ok {
-							continue
-						}
-						if p.Runtime.ShouldRebuild(target.Runtime, target.FunctionID, evt.Path) {
-							for _, worker := range workers {
-								if worker.FunctionID == functionID {
-									slog.Info("stopping", "workerID", worker.WorkerID, "functionID", worker.FunctionID)
-									worker.Worker.Stop()
-								}
-							}
-							delete(builds, functionID)
-							toBuild[functionID] = true
-						}
-					}
-
-					for functionID := range toBuild {
-						output := getBuildOutput(functionID)
-						if output == nil {
-							delete(toBuild, functionID)
-						}
-					}
-
-					for workerID, info := range workers {
-						if toBuild[info.FunctionID] {
-							run(info.FunctionID, workerID)
-						}
-					}
-					break
-				}
 			case m := <-initChan:
 				slog.Info("got init")
-				bytes := m.Payload()
-				workerID := strings.Split(m.Topic(), "/")[3]
-				existingWorker, exists := workers[workerID]
-				if exists {
+				workerID := strings.Split(m.Topic, "/")[3]
+				if _, ok := serving[workerID]; ok {
 					continue
-					existingWorker.Worker.Stop()
 				}
 				var payload struct {
 					FunctionID string   `json:"functionID"`
 					Env        []string `json:"env"`
 				}
-				err := json.Unmarshal(bytes, &payload)
+				err := json.Unmarshal(m.Payload, &payload)
 				if err != nil {
 					continue
 				}
-				if _, ok := targets[payload.FunctionID]; !ok {
+				if !pool.Ready(payload.FunctionID) {
 					go func() {
 						slog.Info("dev not ready yet", "functionID", payload.FunctionID)
 						time.Sleep(time.Second * 1)
@@ -462,33 +279,18 @@ func Start(
 					}()
 					continue
 				}
-				workerEnv[workerID] = payload.Env
-				if ok := run(payload.FunctionID, workerID); !ok {
-					result, _ := http.Post("http://"+server+workerID+"/runtime/init/error", "application/json", strings.NewReader(`{"errorMessage":"Function failed to build"}`))
-					defer result.Body.Close()
-					body, _ := io.ReadAll(result.Body)
-					slog.Info("error", "body", string(body), "status", result.StatusCode)
-
-					if result.StatusCode != 202 {
-						result, _ := http.Get("http://" + server + workerID + "/runtime/invocation/next")
-						requestID := result.Header.Get("lambda-runtime-aws-request-id")
-						result, _ = http.Post("http://"+server+workerID+"/runtime/invocation/"+requestID+"/error", "application/json", strings.NewReader(`{"errorMessage":"Function failed to build"}`))
-						defer result.Body.Close()
-						body, _ := io.ReadAll(result.Body)
-						slog.Info("error", "body", string(body), "status", result.StatusCode)
-					}
-				}
-				break
-
+				pool.SetEnv(payload.FunctionID, payload.Env)
+				workerCtx, cancel := context.WithCancel(ctx)
+				serving[workerID] = cancel
+				go serve(workerCtx, workerID, payload.FunctionID)
 			case m := <-shutdownChan:
-				workerID := strings.Split(m.Topic(), "/")[3]
-				info, ok := workers[workerID]
+				workerID := strings.Split(m.Topic, "/")[3]
+				cancel, ok := serving[workerID]
 				if !ok {
 					continue
 				}
-				info.Worker.Stop()
-				delete(workers, workerID)
-				delete(workerEnv, workerID)
+				cancel()
+				delete(serving, workerID)
 			}
 		}
 	}()
@@ -498,7 +300,7 @@ func Start(
 		slog.Info("lambda request", "path", path)
 		workerID := path[2]
 		requestID := util.RandomString(8)
-		writer := iot_writer.New(mqttClient, s3Client, bootstrapData.Asset, prefix+"/"+workerID+"/request/"+requestID)
+		writer := iot_writer.New(client, s3Client, bucket, prefix+"/"+workerID+"/request/"+requestID)
 		read, write := io.Pipe()
 		pending.Store(requestID, write)
 		defer func() {
@@ -516,12 +318,20 @@ func Start(
 		}
 		fmt.Fprint(writer, "Connection: close\r\n")
 		fmt.Fprint(writer, "Host: 127.0.0.1\r\n")
-		_, err := fmt.Fprint(writer, "\r\n")
+		if r.ContentLength < 0 {
+			// streaming responses are sent without a length
+			fmt.Fprint(writer, "Transfer-Encoding: chunked\r\n")
+		}
+		fmt.Fprint(writer, "\r\n")
 
-		requestBody := &bytes.Buffer{}
 		if r.ContentLength > 0 {
-			write := io.MultiWriter(writer, requestBody)
-			io.Copy(write, r.Body)
+			io.Copy(writer, r.Body)
+		}
+		if r.ContentLength < 0 {
+			chunked := httputil.NewChunkedWriter(writer)
+			io.Copy(chunked, r.Body)
+			chunked.Close()
+			fmt.Fprint(writer, "\r\n")
 		}
 		writer.Close()
 
@@ -540,23 +350,12 @@ func Start(
 
 		slog.Info("lambda waiting for response", "workerID", workerID)
 
-		done := make(chan struct{})
+		done := make(chan struct{}, 2)
 		go func() {
-			buf := &bytes.Buffer{}
-			write := io.MultiWriter(conn, buf)
-			_, err = io.Copy(write, read)
+			_, err := io.Copy(conn, read)
 			if err != nil {
 				slog.Error("error writing to the connection", "error", err)
 			}
-			resp, err := http.ReadResponse(bufio.NewReader(buf), nil)
-			if err == nil {
-				workerResponseChan <- workerResponse{
-					workerID:    workerID,
-					response:    resp,
-					requestBody: requestBody,
-					path:        path,
-				}
-			}
 			done <- struct{}{}
 		}()
 
@@ -576,6 +375,75 @@ func Start(
 	})
 
 	<-ctx.Done()
-	mqttClient.Disconnect(250)
+	client.Close()
 	return nil
 }
+
+// respond sends the result of an invocation back to the Runtime API of the
+// remote execution environment.
+func respond(ctx context.Context, url string, result *lambda.Result) error {
+	var req *http.Request
+	if result.Error != nil {
+		body, _ := json.Marshal(result.Error)
+		req, _ = http.NewRequestWithContext(ctx, http.MethodPost, url+"/error", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Lambda-Runtime-Function-Error-Type", result.Error.ErrorType)
+	} else {
+		req, _ = http.NewRequestWithContext(ctx, http.MethodPost, url+"/response", bytes.NewReader(result.Output))
+		for name, values := range result.Headers {
+			req.Header[name] = values
+		}
+		if req.Header.Get("Lambda-Runtime-Function-Response-Mode") == "streaming" {
+			req.ContentLength = -1
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// RelayToken returns the token that the relay and the functions use. If
+// SST_LIVE_RELAY_TOKEN is not set, one is generated and kept in the working
+// directory so the functions don't have to be redeployed every time.
+func RelayToken(p *project.Project) (string, error) {
+	if flag.SST_LIVE_RELAY_TOKEN != "" {
+		return flag.SST_LIVE_RELAY_TOKEN, nil
+	}
+	path := filepath.Join(p.PathWorkingDir(), "relay.token")
+	data, err := os.ReadFile(path)
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		return string(bytes.TrimSpace(data)), nil
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	result := hex.EncodeToString(token)
+	if err := os.WriteFile(path, []byte(result), 0600); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// connect uses the websocket relay in SST_LIVE_RELAY if it is set, otherwise
+// AWS IoT.
+func connect(ctx context.Context, config awssdk.Config) (transport.Transport, error) {
+	if flag.SST_LIVE_RELAY != "" {
+		slog.Info("connecting to relay", "url", flag.SST_LIVE_RELAY)
+		return transport.NewRelay(ctx, flag.SST_LIVE_RELAY, transport.RelayOptions{
+			Token:         flag.SST_LIVE_RELAY_TOKEN,
+			AutoReconnect: true,
+		})
+	}
+	return transport.NewIoT(ctx, config, transport.MQTTOptions{
+		ClientID:      hex.EncodeToString(func(b []byte) []byte { _, _ = rand.Read(b); return b }(make([]byte, 16))),
+		AutoReconnect: true,
+	})
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/sst/ion/cmd/sst/mosaic/aws/iot_writer"
+	"github.com/sst/ion/cmd/sst/mosaic/aws/transport"
+	"github.com/sst/ion/pkg/flag"
+	"github.com/sst/ion/pkg/runtime/lambda"
+	"github.com/sst/ion/pkg/server"
+)
+
+type fakePool struct {
+	mu          sync.Mutex
+	env         []string
+	invocations []*lambda.Invocation
+}
+
+func (p *fakePool) Ready(functionID string) bool { return true }
+
+func (p *fakePool) SetEnv(functionID string, env []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.env = env
+}
+
+func (p *fakePool) Send(ctx context.Context, functionID string, invocation *lambda.Invocation) (*lambda.Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invocations = append(p.invocations, invocation)
+	return &lambda.Result{RequestID: invocation.RequestID, Output: []byte(`"pong"`)}, nil
+}
+
+// TestStartRelay runs a live function through the relay served by the dev
+// server itself, with the function's side of the bridge played by the test.
+func TestStartRelay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	url := fmt.Sprintf("ws://127.0.0.1:%d/relay", port)
+	relay, token := flag.SST_LIVE_RELAY, flag.SST_LIVE_RELAY_TOKEN
+	flag.SST_LIVE_RELAY, flag.SST_LIVE_RELAY_TOKEN = url, "secret"
+	defer func() { flag.SST_LIVE_RELAY, flag.SST_LIVE_RELAY_TOKEN = relay, token }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &server.Server{Port: port, Mux: http.NewServeMux()}
+	SetupRelay(s)
+	pool := &fakePool{}
+	started := make(chan error, 1)
+	go func() {
+		client, err := connect(ctx, awssdk.Config{})
+		if err != nil {
+			started <- err
+			return
+		}
+		started <- run(ctx, s, pool, client, nil, "", "ion/app/dev")
+	}()
+	// the dev server starts listening after the relay client tries to connect
+	time.Sleep(100 * time.Millisecond)
+	listener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := &http.Server{Handler: s.Mux}
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	function, err := transport.NewRelay(ctx, url, transport.RelayOptions{Token: "secret", AutoReconnect: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer function.Close()
+	requests := make(chan iot_writer.ReadMsg, 10)
+	reader := iot_writer.NewReader(nil)
+	received := map[string][]byte{}
+	err = function.Subscribe("ion/app/dev/w1/request/#", func(m transport.Message) {
+		for _, msg := range reader.Read(m) {
+			if len(msg.Data) > 0 {
+				received[msg.ID] = append(received[msg.ID], msg.Data...)
+				continue
+			}
+			requests <- iot_writer.ReadMsg{ID: msg.ID, Data: received[msg.ID]}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() iot_writer.ReadMsg {
+		select {
+		case request := <-requests:
+			return request
+		case err := <-started:
+			t.Fatalf("expected the dev server to keep running, got %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for a request")
+		}
+		return iot_writer.ReadMsg{}
+	}
+	respond := func(request iot_writer.ReadMsg, response string) {
+		writer := iot_writer.New(function, nil, "", "ion/app/dev/w1/response/"+request.ID)
+		writer.Write([]byte(response))
+		writer.Close()
+	}
+
+	// the init is dropped until the dev server has subscribed
+	init, _ := json.Marshal(map[string]interface{}{"functionID": "Api", "env": []string{"FOO=bar"}})
+	var request iot_writer.ReadMsg
+	for request.ID == "" {
+		function.Publish("ion/app/dev/w1/init", init)
+		select {
+		case request = <-requests:
+		case err := <-started:
+			t.Fatalf("expected the dev server to keep running, got %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	if !bytes.HasPrefix(request.Data, []byte("GET /2018-06-01/runtime/invocation/next HTTP/1.1\r\n")) {
+		t.Fatalf("unexpected request %q", request.Data)
+	}
+	respond(request, "HTTP/1.1 200 OK\r\nLambda-Runtime-Aws-Request-Id: r1\r\nContent-Length: 4\r\n\r\nping")
+
+	request = next()
+	if !bytes.HasPrefix(request.Data, []byte("POST /2018-06-01/runtime/invocation/r1/response HTTP/1.1\r\n")) || !strings.HasSuffix(string(request.Data), `"pong"`) {
+		t.Fatalf("unexpected response %q", request.Data)
+	}
+	respond(request, "HTTP/1.1 202 Accepted\r\nContent-Length: 0\r\n\r\n")
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if !slices.Equal(pool.env, []string{"FOO=bar"}) {
+		t.Fatalf("unexpected env %v", pool.env)
+	}
+	if len(pool.invocations) != 1 || pool.invocations[0].RequestID != "r1" || string(pool.invocations[0].Payload) != "ping" {
+		t.Fatalf("unexpected invocations %v", pool.invocations)
+	}
+}
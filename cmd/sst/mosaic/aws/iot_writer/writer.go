@@ -11,7 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go/aws"
-	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sst/ion/cmd/sst/mosaic/aws/transport"
 	"github.com/sst/ion/internal/util"
 )
 
@@ -23,12 +23,15 @@ type IoTWriter struct {
 	count  int
 	s3     *s3.Client
 	bucket string
-	client MQTT.Client
+	client transport.Transport
 	buffer []byte // buffer to accumulate data
 	last   time.Time
 }
 
-func New(client MQTT.Client, s3 *s3.Client, bucket string, topic string) *IoTWriter {
+// New creates a writer that publishes data to the topic in chunks. Data that
+// doesn't fit in a few chunks is uploaded to the bucket instead, unless there
+// is no s3 client, in which case every chunk is published.
+func New(client transport.Transport, s3 *s3.Client, bucket string, topic string) *IoTWriter {
 	return &IoTWriter{
 		client: client,
 		buffer: make([]byte, 0, BUFFER_SIZE),
@@ -43,7 +46,7 @@ func (iw *IoTWriter) Write(p []byte) (int, error) {
 	totalWritten := 0
 
 	for len(p) > 0 {
-		if iw.count == MAX_COUNT {
+		if iw.count == MAX_COUNT && iw.s3 != nil {
 			iw.buffer = append(iw.buffer, p...)
 			break
 		}
@@ -77,9 +80,8 @@ func (iw *IoTWriter) flush() error {
 		binary.BigEndian.PutUint32(buf[:], uint32(iw.count))
 		iw.buffer = append(buf[:], iw.buffer...)
 		iw.count++
-		token := iw.client.Publish(iw.topic, 1, false, iw.buffer)
-		if token.Wait() && token.Error() != nil {
-			return token.Error()
+		if err := iw.client.Publish(iw.topic, iw.buffer); err != nil {
+			return err
 		}
 		iw.last = time.Now()
 		iw.buffer = iw.buffer[:0]
@@ -102,16 +104,14 @@ func (iw *IoTWriter) Close() error {
 		binary.BigEndian.PutUint32(bytes, uint32(iw.count))
 		iw.count++
 		bytes = append(bytes, []byte("blk"+iw.bucket+"|"+key)...)
-		token := iw.client.Publish(iw.topic, 1, false, bytes)
-		if token.Wait() && token.Error() != nil {
-			return token.Error()
+		if err := iw.client.Publish(iw.topic, bytes); err != nil {
+			return err
 		}
 	}
 	bytes := make([]byte, 4)
 	binary.BigEndian.PutUint32(bytes, uint32(iw.count))
-	token := iw.client.Publish(iw.topic, 1, false, bytes)
-	if token.Wait() && token.Error() != nil {
-		return token.Error()
+	if err := iw.client.Publish(iw.topic, bytes); err != nil {
+		return err
 	}
 	slog.Info("closed iot writer", "topic", iw.topic, "count", iw.count)
 	return nil
@@ -136,9 +136,9 @@ func NewReader(s3 *s3.Client) *Reader {
 	}
 }
 
-func (r *Reader) Read(m MQTT.Message) []ReadMsg {
-	payload := m.Payload()
-	topic := m.Topic()
+func (r *Reader) Read(m transport.Message) []ReadMsg {
+	payload := m.Payload
+	topic := m.Topic
 	requestID := strings.Split(topic, "/")[5]
 	requestBuffer, ok := r.buffer[requestID]
 	if !ok {
@@ -0,0 +1,64 @@
+package iot_writer
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/mosaic/aws/transport"
+)
+
+func TestWriterOverRelay(t *testing.T) {
+	server := httptest.NewServer(transport.NewRelayServer("secret"))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, err := transport.NewRelay(context.Background(), url, transport.RelayOptions{Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	received := make(chan ReadMsg, 100)
+	reader := NewReader(nil)
+	err = client.Subscribe("ion/app/dev/worker/response/#", func(m transport.Message) {
+		for _, msg := range reader.Read(m) {
+			received <- msg
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// larger than MAX_COUNT chunks, which would go through s3 with IoT
+	data := bytes.Repeat([]byte("abcdefgh"), BUFFER_SIZE)
+	writer := New(client, nil, "", "ion/app/dev/worker/response/abc")
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := []byte{}
+	for {
+		select {
+		case msg := <-received:
+			if msg.ID != "abc" {
+				t.Fatalf("unexpected request %s", msg.ID)
+			}
+			if len(msg.Data) == 0 {
+				if !bytes.Equal(result, data) {
+					t.Fatalf("expected %d bytes, got %d", len(data), len(result))
+				}
+				return
+			}
+			result = append(result, msg.Data...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %d bytes", len(result))
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/iot"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+type MQTTOptions struct {
+	ClientID      string
+	AutoReconnect bool
+}
+
+type mqttTransport struct {
+	client MQTT.Client
+}
+
+// NewIoT connects to the AWS IoT endpoint of the account over a presigned
+// websocket.
+func NewIoT(ctx context.Context, config aws.Config, options MQTTOptions) (Transport, error) {
+	expire := time.Hour * 24
+	from := time.Now()
+
+	slog.Info("getting endpoint")
+	iotClient := iot.NewFromConfig(config)
+	endpointResp, err := iotClient.DescribeEndpoint(ctx, &iot.DescribeEndpointInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	originalURL, err := url.Parse(fmt.Sprintf("wss://%s/mqtt?X-Amz-Expires=%s", *endpointResp.EndpointAddress, strconv.FormatInt(int64(expire/time.Second), 10)))
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("found endpoint endpoint", "url", originalURL.String())
+
+	creds, err := config.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessionToken := creds.SessionToken
+	creds.SessionToken = ""
+
+	signer := v4.NewSigner()
+	req := &http.Request{
+		Method: "GET",
+		URL:    originalURL,
+	}
+
+	presignedURL, _, err := signer.PresignHTTP(ctx, creds, req, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "iotdevicegateway", config.Region, from)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("signed request", "url", presignedURL)
+	if sessionToken != "" {
+		presignedURL += "&X-Amz-Security-Token=" + url.QueryEscape(sessionToken)
+	}
+	return NewMQTT(presignedURL, options)
+}
+
+// NewMQTT connects to an MQTT broker. This is what the IoT transport uses
+// after presigning its endpoint.
+func NewMQTT(broker string, options MQTTOptions) (Transport, error) {
+	opts := MQTT.
+		NewClientOptions().
+		AddBroker(broker).
+		SetClientID(options.ClientID).
+		SetTLSConfig(&tls.Config{
+			InsecureSkipVerify: true,
+		}).
+		SetWebsocketOptions(&MQTT.WebsocketOptions{
+			ReadBufferSize:  1024 * 1000,
+			WriteBufferSize: 1024 * 1000,
+		}).
+		SetCleanSession(false).
+		SetAutoReconnect(options.AutoReconnect).
+		SetMaxReconnectInterval(time.Second * 1).
+		SetConnectionLostHandler(func(c MQTT.Client, err error) {
+			slog.Info("mqtt connection lost", "error", err)
+		}).
+		SetReconnectingHandler(func(c MQTT.Client, co *MQTT.ClientOptions) {
+			slog.Info("mqtt reconnecting")
+		}).
+		SetOnConnectHandler(func(c MQTT.Client) {
+			slog.Info("mqtt connected")
+		}).
+		SetKeepAlive(time.Second * 1200).
+		SetPingTimeout(time.Second * 60)
+
+	client := MQTT.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("failed to connect to mqtt: %w", token.Error())
+	}
+	return &mqttTransport{client: client}, nil
+}
+
+func (t *mqttTransport) Publish(topic string, payload []byte) error {
+	token := t.client.Publish(topic, 1, false, payload)
+	token.Wait()
+	return token.Error()
+}
+
+func (t *mqttTransport) Subscribe(topic string, handler func(Message)) error {
+	token := t.client.Subscribe(topic, 1, func(c MQTT.Client, m MQTT.Message) {
+		handler(Message{Topic: m.Topic(), Payload: m.Payload()})
+	})
+	token.Wait()
+	return token.Error()
+}
+
+func (t *mqttTransport) Close() error {
+	t.client.Disconnect(250)
+	return nil
+}
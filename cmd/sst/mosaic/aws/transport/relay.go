@@ -0,0 +1,269 @@
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// frame is what the relay and its clients send over the websocket.
+type frame struct {
+	Type    string `json:"type"`
+	Topic   string `json:"topic"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+var ErrRelayUnauthorized = fmt.Errorf("relay rejected the token")
+
+type RelayOptions struct {
+	Token         string
+	AutoReconnect bool
+}
+
+type relayTransport struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	url     string
+	options RelayOptions
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	handlers map[string]func(Message)
+}
+
+// NewRelay connects to a websocket relay. Unlike IoT, this only needs the
+// relay to be reachable from the function and from sst dev.
+func NewRelay(ctx context.Context, url string, options RelayOptions) (Transport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &relayTransport{
+		ctx:      ctx,
+		cancel:   cancel,
+		url:      url,
+		options:  options,
+		handlers: map[string]func(Message){},
+	}
+	conn, err := t.dial()
+	// the relay can be served by sst dev itself, which might not be listening yet
+	for err != nil && err != ErrRelayUnauthorized && options.AutoReconnect {
+		slog.Info("relay not reachable, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			cancel()
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+		conn, err = t.dial()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	t.conn = conn
+	go t.read(conn)
+	return t, nil
+}
+
+func (t *relayTransport) dial() (*websocket.Conn, error) {
+	header := http.Header{}
+	if t.options.Token != "" {
+		header.Set("Authorization", "Bearer "+t.options.Token)
+	}
+	conn, resp, err := websocket.DefaultDialer.DialContext(t.ctx, t.url, header)
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrRelayUnauthorized
+	}
+	return conn, err
+}
+
+func (t *relayTransport) read(conn *websocket.Conn) {
+	for {
+		var msg frame
+		err := conn.ReadJSON(&msg)
+		if err != nil {
+			slog.Info("relay connection lost", "error", err)
+			conn.Close()
+			if t.ctx.Err() != nil || !t.options.AutoReconnect {
+				return
+			}
+			t.reconnect()
+			return
+		}
+		if msg.Type != "message" {
+			continue
+		}
+		t.mu.Lock()
+		handlers := []func(Message){}
+		for pattern, handler := range t.handlers {
+			if Match(pattern, msg.Topic) {
+				handlers = append(handlers, handler)
+			}
+		}
+		t.mu.Unlock()
+		for _, handler := range handlers {
+			handler(Message{Topic: msg.Topic, Payload: msg.Payload})
+		}
+	}
+}
+
+func (t *relayTransport) reconnect() {
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+		slog.Info("relay reconnecting")
+		conn, err := t.dial()
+		if err != nil {
+			continue
+		}
+		t.mu.Lock()
+		t.conn = conn
+		for pattern := range t.handlers {
+			conn.WriteJSON(&frame{Type: "subscribe", Topic: pattern})
+		}
+		t.mu.Unlock()
+		go t.read(conn)
+		return
+	}
+}
+
+func (t *relayTransport) send(msg *frame) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn.WriteJSON(msg)
+}
+
+func (t *relayTransport) Publish(topic string, payload []byte) error {
+	return t.send(&frame{Type: "publish", Topic: topic, Payload: payload})
+}
+
+func (t *relayTransport) Subscribe(topic string, handler func(Message)) error {
+	t.mu.Lock()
+	t.handlers[topic] = handler
+	t.mu.Unlock()
+	return t.send(&frame{Type: "subscribe", Topic: topic})
+}
+
+func (t *relayTransport) Close() error {
+	t.cancel()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn.Close()
+}
+
+// Relay is a websocket server that forwards published messages to every
+// client subscribed to a matching topic. sst dev serves one at /relay so it
+// can be exposed through a tunnel, or it can be run on its own.
+type Relay struct {
+	token    string
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*relayClient]struct{}
+}
+
+type relayClient struct {
+	mu            sync.Mutex
+	conn          *websocket.Conn
+	subscriptions map[string]bool
+}
+
+// NewRelayServer creates a relay that only accepts clients with the token. If
+// the token is empty every client is rejected. The default origin check stops
+// web pages on other hosts from connecting.
+func NewRelayServer(token string) *Relay {
+	return &Relay{
+		token:   token,
+		clients: map[*relayClient]struct{}{},
+	}
+}
+
+func (r *Relay) authorized(req *http.Request) bool {
+	if r.token == "" {
+		return false
+	}
+	candidate := req.URL.Query().Get("token")
+	if bearer, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		candidate = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(candidate), []byte(r.token)) == 1
+}
+
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !r.authorized(req) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	conn, err := r.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	client := &relayClient{
+		conn:          conn,
+		subscriptions: map[string]bool{},
+	}
+	r.mu.Lock()
+	r.clients[client] = struct{}{}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.clients, client)
+		r.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var msg frame
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case "subscribe":
+			client.mu.Lock()
+			client.subscriptions[msg.Topic] = true
+			client.mu.Unlock()
+		case "unsubscribe":
+			client.mu.Lock()
+			delete(client.subscriptions, msg.Topic)
+			client.mu.Unlock()
+		case "publish":
+			r.publish(&frame{Type: "message", Topic: msg.Topic, Payload: msg.Payload})
+		}
+	}
+}
+
+func (r *Relay) publish(msg *frame) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	clients := make([]*relayClient, 0, len(r.clients))
+	for client := range r.clients {
+		clients = append(clients, client)
+	}
+	r.mu.Unlock()
+	for _, client := range clients {
+		client.mu.Lock()
+		matched := false
+		for pattern := range client.subscriptions {
+			if Match(pattern, msg.Topic) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			client.conn.WriteMessage(websocket.TextMessage, data)
+		}
+		client.mu.Unlock()
+	}
+}
@@ -0,0 +1,38 @@
+package transport
+
+import (
+	"strings"
+)
+
+// Message is a payload published to a topic.
+type Message struct {
+	Topic   string `json:"topic"`
+	Payload []byte `json:"payload"`
+}
+
+// Transport carries messages between the bridge function and sst dev. Topics
+// are separated by slashes and subscriptions can use MQTT style wildcards, +
+// for a single level and # for the rest of the topic.
+type Transport interface {
+	Publish(topic string, payload []byte) error
+	Subscribe(topic string, handler func(Message)) error
+	Close() error
+}
+
+// Match reports whether the topic matches the subscription pattern.
+func Match(pattern string, topic string) bool {
+	patternParts := strings.Split(pattern, "/")
+	topicParts := strings.Split(topic, "/")
+	for i, part := range patternParts {
+		if part == "#" {
+			return true
+		}
+		if i >= len(topicParts) {
+			return false
+		}
+		if part != "+" && part != topicParts[i] {
+			return false
+		}
+	}
+	return len(patternParts) == len(topicParts)
+}
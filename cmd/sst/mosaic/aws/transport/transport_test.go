@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"ion/app/dev/+/init", "ion/app/dev/worker/init", true},
+		{"ion/app/dev/+/init", "ion/app/dev/worker/shutdown", false},
+		{"ion/app/dev/+/response/#", "ion/app/dev/worker/response/abc", true},
+		{"ion/app/dev/worker/request/#", "ion/app/dev/worker/request/abc", true},
+		{"ion/app/dev/worker/ack", "ion/app/dev/worker/ack", true},
+		{"ion/app/dev/worker/ack", "ion/app/dev/worker/ack/extra", false},
+		{"ion/app/dev/+/init", "ion/app/dev/init", false},
+	}
+	for _, test := range tests {
+		if Match(test.pattern, test.topic) != test.match {
+			t.Errorf("Match(%q, %q) should be %v", test.pattern, test.topic, test.match)
+		}
+	}
+}
+
+// exercise publishes from one transport and expects the other to receive only
+// the messages matching its subscriptions.
+func exercise(t *testing.T, cli Transport, bridge Transport) {
+	received := make(chan Message, 10)
+	if err := cli.Subscribe("ion/app/dev/+/init", func(m Message) { received <- m }); err != nil {
+		t.Fatal(err)
+	}
+	if err := bridge.Subscribe("ion/app/dev/worker/request/#", func(m Message) { received <- m }); err != nil {
+		t.Fatal(err)
+	}
+	// give the subscriptions time to reach the broker
+	time.Sleep(50 * time.Millisecond)
+
+	if err := bridge.Publish("ion/app/dev/worker/shutdown", []byte("ignored")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bridge.Publish("ion/app/dev/worker/init", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Publish("ion/app/dev/worker/request/abc", []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ion/app/dev/worker/init":        "hello",
+		"ion/app/dev/worker/request/abc": "world",
+	}
+	for len(expected) > 0 {
+		select {
+		case m := <-received:
+			payload, ok := expected[m.Topic]
+			if !ok || payload != string(m.Payload) {
+				t.Fatalf("unexpected message %s %s", m.Topic, m.Payload)
+			}
+			delete(expected, m.Topic)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %v", expected)
+		}
+	}
+}
+
+func TestRelay(t *testing.T) {
+	server := httptest.NewServer(NewRelayServer("secret"))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ctx := context.Background()
+
+	if _, err := NewRelay(ctx, url, RelayOptions{Token: "wrong", AutoReconnect: true}); err != ErrRelayUnauthorized {
+		t.Fatalf("expected a bad token to be rejected without retrying, got %v", err)
+	}
+	header := http.Header{"Authorization": {"Bearer secret"}, "Origin": {"https://example.com"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(url, header); err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatal("expected a page on another host to be rejected")
+	}
+	empty := httptest.NewServer(NewRelayServer(""))
+	defer empty.Close()
+	if _, err := NewRelay(ctx, "ws"+strings.TrimPrefix(empty.URL, "http"), RelayOptions{}); err == nil {
+		t.Fatal("expected a relay without a token to reject every client")
+	}
+
+	cli, err := NewRelay(ctx, url, RelayOptions{Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	bridge, err := NewRelay(ctx, url, RelayOptions{Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+	exercise(t, cli, bridge)
+}
+
+func TestMQTT(t *testing.T) {
+	broker := startBroker(t)
+	cli, err := NewMQTT("tcp://"+broker, MQTTOptions{ClientID: "cli"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	bridge, err := NewMQTT("tcp://"+broker, MQTTOptions{ClientID: "bridge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+	exercise(t, cli, bridge)
+}
+
+// startBroker runs a stand-in for the IoT broker that speaks just enough MQTT
+// 3.1.1 for the transport. Messages are delivered to subscribers with QoS 0.
+func startBroker(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	type client struct {
+		mu            sync.Mutex
+		conn          net.Conn
+		subscriptions []string
+	}
+	var mu sync.Mutex
+	clients := map[*client]bool{}
+
+	write := func(c *client, packet byte, body []byte) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		header := []byte{packet}
+		length := len(body)
+		for {
+			b := byte(length % 128)
+			length /= 128
+			if length > 0 {
+				b |= 128
+			}
+			header = append(header, b)
+			if length == 0 {
+				break
+			}
+		}
+		c.conn.Write(append(header, body...))
+	}
+	str := func(s string) []byte {
+		return append(binary.BigEndian.AppendUint16(nil, uint16(len(s))), s...)
+	}
+
+	handle := func(conn net.Conn) {
+		c := &client{conn: conn}
+		mu.Lock()
+		clients[c] = true
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(clients, c)
+			mu.Unlock()
+			conn.Close()
+		}()
+		reader := bufio.NewReader(conn)
+		for {
+			header, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			length, multiplier := 0, 1
+			for {
+				b, err := reader.ReadByte()
+				if err != nil {
+					return
+				}
+				length += int(b&127) * multiplier
+				multiplier *= 128
+				if b&128 == 0 {
+					break
+				}
+			}
+			body := make([]byte, length)
+			if _, err := io.ReadFull(reader, body); err != nil {
+				return
+			}
+			switch header >> 4 {
+			case 1: // CONNECT
+				write(c, 0x20, []byte{0, 0})
+			case 3: // PUBLISH
+				qos := (header >> 1) & 3
+				topicLength := int(binary.BigEndian.Uint16(body))
+				topic := string(body[2 : 2+topicLength])
+				payload := body[2+topicLength:]
+				if qos > 0 {
+					write(c, 0x40, payload[:2])
+					payload = payload[2:]
+				}
+				mu.Lock()
+				targets := []*client{}
+				for target := range clients {
+					target.mu.Lock()
+					for _, pattern := range target.subscriptions {
+						if Match(pattern, topic) {
+							targets = append(targets, target)
+							break
+						}
+					}
+					target.mu.Unlock()
+				}
+				mu.Unlock()
+				for _, target := range targets {
+					write(target, 0x30, append(str(topic), payload...))
+				}
+			case 8: // SUBSCRIBE
+				granted := []byte{}
+				for rest := body[2:]; len(rest) > 0; {
+					topicLength := int(binary.BigEndian.Uint16(rest))
+					c.mu.Lock()
+					c.subscriptions = append(c.subscriptions, string(rest[2:2+topicLength]))
+					c.mu.Unlock()
+					granted = append(granted, 0)
+					rest = rest[3+topicLength:]
+				}
+				write(c, 0x90, append(body[:2:2], granted...))
+			case 12: // PINGREQ
+				write(c, 0xD0, nil)
+			case 14: // DISCONNECT
+				return
+			}
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
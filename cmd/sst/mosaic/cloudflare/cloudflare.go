@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/cloudflare/cloudflare-go"
 	"github.com/gorilla/websocket"
@@ -110,7 +111,9 @@ exit:
 				builds[target.FunctionID] = output
 			case *watcher.FileChangedEvent:
 				for workerID, target := range targets {
-					if proj.Runtime.ShouldRebuild(target.Runtime, workerID, evt.Path) {
+					if slices.ContainsFunc(evt.Paths(), func(path string) bool {
+						return proj.Runtime.ShouldRebuild(target.Runtime, workerID, path)
+					}) {
 						output, err := proj.Runtime.Build(ctx, target)
 						if err != nil {
 							continue
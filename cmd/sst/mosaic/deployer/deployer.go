@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"reflect"
+	"slices"
 
 	"github.com/sst/ion/cmd/sst/mosaic/errors"
 	"github.com/sst/ion/cmd/sst/mosaic/watcher"
@@ -34,7 +35,7 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 					watchedFiles[file] = true
 				}
 			case *watcher.FileChangedEvent, *DeployRequestedEvent:
-				if evt, ok := evt.(*watcher.FileChangedEvent); !ok || watchedFiles[evt.Path] {
+				if evt, ok := evt.(*watcher.FileChangedEvent); !ok || slices.ContainsFunc(evt.Paths(), func(path string) bool { return watchedFiles[path] }) {
 					slog.Info("deployer deploying")
 					err := p.Run(ctx, &project.StackInput{
 						Command:    "deploy",
@@ -0,0 +1,121 @@
+package dev
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// credentials serves the role credentials of dev processes the way the ECS
+// container credentials endpoint does, so the AWS SDKs in those processes
+// refresh them on their own instead of the process being restarted.
+type credentials struct {
+	url       string
+	lock      sync.Mutex
+	processes map[string]*process
+}
+
+type process struct {
+	token    string
+	role     string
+	provider aws.CredentialsProvider
+}
+
+type credentialsResponse struct {
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+	Expiration      string `json:"Expiration,omitempty"`
+}
+
+func newCredentials(port int) *credentials {
+	return &credentials{
+		url:       fmt.Sprintf("http://127.0.0.1:%d/api/credentials", port),
+		processes: map[string]*process{},
+	}
+}
+
+// Env replaces the credentials in the environment of a dev process with the
+// endpoint and its auth token. Every process gets its own token, which stays
+// the same across calls so the environment doesn't change. The provider is
+// only replaced when the role of the process changes.
+func (c *credentials) Env(name string, role string, provider func() aws.CredentialsProvider, env map[string]string) error {
+	if role == "" {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	match, ok := c.processes[name]
+	if !ok {
+		token, err := newToken()
+		if err != nil {
+			return err
+		}
+		match = &process{token: token}
+		c.processes[name] = match
+	}
+	if match.role != role || match.provider == nil {
+		match.role = role
+		match.provider = provider()
+	}
+	if match.provider == nil {
+		return nil
+	}
+	delete(env, "AWS_ACCESS_KEY_ID")
+	delete(env, "AWS_SECRET_ACCESS_KEY")
+	delete(env, "AWS_SESSION_TOKEN")
+	env["AWS_CONTAINER_CREDENTIALS_FULL_URI"] = c.url
+	env["AWS_CONTAINER_AUTHORIZATION_TOKEN"] = match.token
+	return nil
+}
+
+func (c *credentials) find(token string) aws.CredentialsProvider {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, process := range c.processes {
+		if subtle.ConstantTimeCompare([]byte(process.token), []byte(token)) == 1 {
+			return process.provider
+		}
+	}
+	return nil
+}
+
+func (c *credentials) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	provider := c.find(r.Header.Get("Authorization"))
+	if provider == nil {
+		http.Error(w, "invalid authorization token", http.StatusUnauthorized)
+		return
+	}
+	creds, err := provider.Retrieve(r.Context())
+	if err != nil {
+		slog.Error("failed to retrieve credentials", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result := credentialsResponse{
+		AccessKeyId:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		Token:           creds.SessionToken,
+	}
+	if creds.CanExpire {
+		result.Expiration = creds.Expires.UTC().Format(time.RFC3339)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func newToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
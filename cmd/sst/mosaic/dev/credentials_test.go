@@ -0,0 +1,81 @@
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestCredentials(t *testing.T) {
+	expires := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	provider := func() aws.CredentialsProvider {
+		calls++
+		return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     "key",
+				SecretAccessKey: "secret",
+				SessionToken:    "session",
+				CanExpire:       true,
+				Expires:         expires,
+			}, nil
+		})
+	}
+	creds := newCredentials(13557)
+
+	web := map[string]string{"AWS_ACCESS_KEY_ID": "key", "AWS_REGION": "us-east-1"}
+	if err := creds.Env("Web", "role", provider, web); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := web["AWS_ACCESS_KEY_ID"]; ok {
+		t.Fatal("expected static credentials to be removed")
+	}
+	if web["AWS_CONTAINER_CREDENTIALS_FULL_URI"] != "http://127.0.0.1:13557/api/credentials" {
+		t.Fatalf("unexpected endpoint %q", web["AWS_CONTAINER_CREDENTIALS_FULL_URI"])
+	}
+	token := web["AWS_CONTAINER_AUTHORIZATION_TOKEN"]
+
+	again := map[string]string{}
+	creds.Env("Web", "role", provider, again)
+	if again["AWS_CONTAINER_AUTHORIZATION_TOKEN"] != token || calls != 1 {
+		t.Fatal("expected the token and provider to be reused")
+	}
+	other := map[string]string{}
+	creds.Env("Admin", "role", provider, other)
+	if other["AWS_CONTAINER_AUTHORIZATION_TOKEN"] == token {
+		t.Fatal("expected every process to get its own token")
+	}
+	none := map[string]string{}
+	creds.Env("Docs", "", provider, none)
+	if len(none) != 0 {
+		t.Fatal("expected no endpoint without a role")
+	}
+
+	for _, auth := range []string{"", "wrong"} {
+		req := httptest.NewRequest("GET", "/api/credentials", nil)
+		req.Header.Set("Authorization", auth)
+		w := httptest.NewRecorder()
+		creds.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected %q to be rejected, got %d", auth, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/credentials", nil)
+	req.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+	creds.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	var result credentialsResponse
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.AccessKeyId != "key" || result.Token != "session" || result.Expiration != "2024-06-01T12:00:00Z" {
+		t.Fatalf("unexpected credentials %+v", result)
+	}
+}
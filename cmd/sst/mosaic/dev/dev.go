@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/sst/ion/cmd/sst/mosaic/deployer"
 	"github.com/sst/ion/pkg/bus"
 	"github.com/sst/ion/pkg/project"
@@ -23,6 +24,7 @@ type Message struct {
 
 func Start(ctx context.Context, p *project.Project, server *server.Server) error {
 	var complete *project.CompleteEvent
+	creds := newCredentials(server.Port)
 	var wg errgroup.Group
 	wg.Go(func() error {
 		evts := bus.Subscribe(&project.CompleteEvent{})
@@ -82,7 +84,19 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 			full := filepath.Join(cwd, d.Directory)
 			slog.Info("matching dev", "full", full, "directory", directory)
 			if (directory != "" && full == directory) || (name != "" && d.Name == name) {
-				env, err := p.EnvFor(ctx, complete, d.Name)
+				// the credentials come from the credentials endpoint
+				env, err := p.EnvFor(ctx, complete, d.Name, false)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				role := ""
+				if d.Aws != nil {
+					role = d.Aws.Role
+				}
+				err = creds.Env(d.Name, role, func() aws.CredentialsProvider {
+					return p.CredentialsFor(complete, d.Name)
+				}, env)
 				if err != nil {
 					http.Error(w, err.Error(), http.StatusInternalServerError)
 					return
@@ -103,6 +117,8 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 		return
 	})
 
+	server.Mux.Handle("/api/credentials", creds)
+
 	return wg.Wait()
 }
 
@@ -0,0 +1,664 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
+	"github.com/sst/ion/cmd/sst/mosaic/watcher"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
+	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/runtime"
+	"github.com/sst/ion/pkg/runtime/lambda"
+	"github.com/sst/ion/pkg/server"
+)
+
+// DefaultTimeout is used for functions that are not in the state yet.
+const DefaultTimeout = 15 * time.Minute
+
+// DefaultConcurrency is how many workers a function can have at once, unless
+// SST_DEV_CONCURRENCY is set.
+const DefaultConcurrency = 4
+
+// DefaultIdleTimeout is how long a worker is kept around without invocations,
+// unless SST_DEV_IDLE_TIMEOUT is set in seconds.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// Local runs the functions registered in dev on a pool of local workers per
+// function. The workers poll a local Runtime API, and invocations either come
+// from AWS through the live bridge or are made locally without a round trip
+// through AWS.
+type Local struct {
+	ctx         context.Context
+	api         *lambda.Server
+	runtimes    *runtime.Collection
+	server      string
+	env         func(target *runtime.BuildInput) []string
+	concurrency int
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	targets  map[string]*runtime.BuildInput
+	builds   map[string]*runtime.BuildOutput
+	pools    map[string]*pool
+	timeouts map[string]time.Duration
+	memory   map[string]int
+}
+
+// pool is the workers of a function. Invocations take a slot before they pick
+// an idle worker or start a new one, so a function never has more workers than
+// slots and invocations queue up when they are all taken.
+type pool struct {
+	slots   chan struct{}
+	build   sync.Mutex
+	workers map[string]*worker
+	idle    []*worker
+	queued  int
+	// env is the environment of the function as deployed, sent by the live
+	// bridge. Workers are started with it instead of the local one if it's set.
+	env []string
+	// generation changes with env so workers started with an older one are
+	// not reused.
+	generation int
+}
+
+type worker struct {
+	id         string
+	functionID string
+	worker     runtime.Worker
+	requestID  atomic.Value
+	done       chan struct{}
+	generation int
+	lastUsed   time.Time
+}
+
+// New creates a Local that starts workers pointing at server, the address the
+// Runtime API handler is served on. Workers run until ctx is done.
+func New(ctx context.Context, runtimes *runtime.Collection, server string, env func(target *runtime.BuildInput) []string) *Local {
+	concurrency := DefaultConcurrency
+	if parsed, err := strconv.Atoi(flag.SST_DEV_CONCURRENCY); err == nil && parsed > 0 {
+		concurrency = parsed
+	}
+	idleTimeout := DefaultIdleTimeout
+	if parsed, err := strconv.Atoi(flag.SST_DEV_IDLE_TIMEOUT); err == nil && parsed > 0 {
+		idleTimeout = time.Duration(parsed) * time.Second
+	}
+	return &Local{
+		ctx:         ctx,
+		api:         lambda.New(),
+		runtimes:    runtimes,
+		server:      strings.TrimSuffix(server, "/"),
+		env:         env,
+		concurrency: concurrency,
+		idleTimeout: idleTimeout,
+		targets:     map[string]*runtime.BuildInput{},
+		builds:      map[string]*runtime.BuildOutput{},
+		pools:       map[string]*pool{},
+		timeouts:    map[string]time.Duration{},
+		memory:      map[string]int{},
+	}
+}
+
+// Handler serves the Runtime API that the workers poll.
+func (l *Local) Handler() http.Handler {
+	return l.api
+}
+
+func (l *Local) AddTarget(input *runtime.BuildInput) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.targets[input.FunctionID] = input
+}
+
+// Ready reports whether the function has been registered in dev so it can be
+// invoked.
+func (l *Local) Ready(functionID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.targets[functionID]
+	return ok
+}
+
+func (l *Local) SetTimeout(functionID string, timeout time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeouts[functionID] = timeout
+}
+
+// SetMemory sets the memory in MB that new workers of a function are limited
+// to.
+func (l *Local) SetMemory(functionID string, memory int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.memory[functionID] = memory
+}
+
+// SetEnv sets the environment the workers of a function are started with. If
+// it changed, the idle workers are stopped so the next invocation gets a
+// worker with the new one. The AWS_ variables are ignored when comparing since
+// the credentials are different for every execution environment.
+func (l *Local) SetEnv(functionID string, env []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p := l.pool(functionID)
+	if p.env != nil && slices.Equal(withoutAWS(p.env), withoutAWS(env)) {
+		return
+	}
+	p.env = env
+	p.generation++
+	for _, w := range slices.Clone(p.idle) {
+		l.stop(p, w)
+	}
+	l.publish(functionID)
+}
+
+func withoutAWS(env []string) []string {
+	result := []string{}
+	for _, item := range env {
+		if !strings.HasPrefix(item, "AWS_") {
+			result = append(result, item)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// pool returns the pool of a function, creating it if needed. It must be
+// called with the lock held.
+func (l *Local) pool(functionID string) *pool {
+	p, ok := l.pools[functionID]
+	if !ok {
+		p = &pool{
+			slots:   make(chan struct{}, l.concurrency),
+			workers: map[string]*worker{},
+		}
+		l.pools[functionID] = p
+	}
+	return p
+}
+
+// stop stops a worker and forgets it. It must be called with the lock held.
+func (l *Local) stop(p *pool, w *worker) {
+	delete(p.workers, w.id)
+	p.idle = slices.DeleteFunc(p.idle, func(item *worker) bool { return item == w })
+	w.worker.Stop()
+}
+
+// FileChanged stops the workers of the functions that depend on the files and
+// rebuilds them in the background so build errors show up right away. Every
+// function is rebuilt once, however many of its files changed.
+func (l *Local) FileChanged(paths ...string) {
+	l.mu.Lock()
+	rebuild := []string{}
+	for functionID := range l.builds {
+		target, ok := l.targets[functionID]
+		if !ok || !slices.ContainsFunc(paths, func(path string) bool {
+			return l.runtimes.ShouldRebuild(target.Runtime, functionID, path)
+		}) {
+			continue
+		}
+		delete(l.builds, functionID)
+		rebuild = append(rebuild, functionID)
+		p := l.pool(functionID)
+		for _, w := range p.workers {
+			slog.Info("stopping local worker", "workerID", w.id, "functionID", functionID)
+			l.stop(p, w)
+		}
+		l.publish(functionID)
+	}
+	l.mu.Unlock()
+	for _, functionID := range rebuild {
+		go l.build(functionID)
+	}
+}
+
+// Reap stops the workers that have not been used for longer than the idle
+// timeout.
+func (l *Local) Reap() {
+	l.reap(time.Now())
+}
+
+func (l *Local) reap(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for functionID, p := range l.pools {
+		reaped := false
+		for _, w := range slices.Clone(p.idle) {
+			if now.Sub(w.lastUsed) < l.idleTimeout {
+				continue
+			}
+			slog.Info("reaping idle worker", "workerID", w.id, "functionID", functionID)
+			l.stop(p, w)
+			reaped = true
+		}
+		if reaped {
+			l.publish(functionID)
+		}
+	}
+}
+
+// Stop stops every worker.
+func (l *Local) Stop() {
+	l.mu.Lock()
+	workers := []*worker{}
+	for _, p := range l.pools {
+		for _, w := range p.workers {
+			workers = append(workers, w)
+			l.stop(p, w)
+		}
+	}
+	l.mu.Unlock()
+	for _, w := range workers {
+		<-w.done
+	}
+}
+
+// publish sends the state of the workers of a function to the UI. It must be
+// called with the lock held.
+func (l *Local) publish(functionID string) {
+	p := l.pool(functionID)
+	bus.Publish(&aws.FunctionWorkersEvent{
+		FunctionID: functionID,
+		Active:     len(p.workers) - len(p.idle),
+		Idle:       len(p.idle),
+		Queued:     p.queued,
+		Max:        l.concurrency,
+	})
+}
+
+// Invoke runs the function with the payload, building it and starting a worker
+// if needed. The worker is reused for later invocations.
+func (l *Local) Invoke(ctx context.Context, functionID string, payload []byte) (*lambda.Result, error) {
+	return l.Send(ctx, functionID, &lambda.Invocation{Payload: payload})
+}
+
+// Send runs an invocation on one of the workers of the function, waiting for
+// one if the function is at its concurrency limit. The request ID and deadline
+// of the invocation are used if they are set, like for invocations that came
+// from AWS.
+func (l *Local) Send(ctx context.Context, functionID string, invocation *lambda.Invocation) (*lambda.Result, error) {
+	l.mu.Lock()
+	if _, ok := l.targets[functionID]; !ok {
+		l.mu.Unlock()
+		return nil, ErrFunctionNotFound
+	}
+	p := l.pool(functionID)
+	p.queued++
+	l.publish(functionID)
+	l.mu.Unlock()
+
+	acquired := false
+	select {
+	case p.slots <- struct{}{}:
+		acquired = true
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	p.queued--
+	l.mu.Unlock()
+	if acquired {
+		defer func() { <-p.slots }()
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	w, result, err := l.acquire(functionID)
+	if err != nil || result != nil {
+		l.mu.Lock()
+		l.publish(functionID)
+		l.mu.Unlock()
+		return result, err
+	}
+
+	if invocation.RequestID == "" {
+		invocation.RequestID = lambda.NewRequestID()
+	}
+	if invocation.Deadline.IsZero() {
+		l.mu.Lock()
+		timeout, ok := l.timeouts[functionID]
+		l.mu.Unlock()
+		if !ok || l.runtimes.Inspecting(functionID) {
+			timeout = DefaultTimeout
+		}
+		invocation.Deadline = time.Now().Add(timeout)
+	}
+	w.requestID.Store(invocation.RequestID)
+	bus.Publish(&aws.FunctionInvokedEvent{
+		FunctionID: functionID,
+		WorkerID:   w.id,
+		RequestID:  invocation.RequestID,
+		Input:      invocation.Payload,
+	})
+	result, err = l.api.Send(ctx, w.id, invocation)
+	// like lambda, a timed out sandbox is not reused
+	l.release(p, w, err == nil && (result.Error == nil || result.Error.ErrorType != "Sandbox.Timedout"))
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		bus.Publish(&aws.FunctionErrorEvent{
+			FunctionID:   functionID,
+			WorkerID:     w.id,
+			RequestID:    invocation.RequestID,
+			ErrorType:    result.Error.ErrorType,
+			ErrorMessage: result.Error.ErrorMessage,
+			Trace:        result.Error.Trace,
+		})
+		return result, nil
+	}
+	bus.Publish(&aws.FunctionResponseEvent{
+		FunctionID: functionID,
+		WorkerID:   w.id,
+		RequestID:  invocation.RequestID,
+		Output:     result.Output,
+	})
+	return result, nil
+}
+
+// release puts a worker back in the pool after an invocation, or stops it if
+// it should not be reused.
+func (l *Local) release(p *pool, w *worker, reuse bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.publish(w.functionID)
+	if _, ok := p.workers[w.id]; !ok {
+		return
+	}
+	if !reuse || w.generation != p.generation {
+		l.stop(p, w)
+		return
+	}
+	w.lastUsed = time.Now()
+	p.idle = append(p.idle, w)
+}
+
+// build returns the build of a function, building it if needed. Functions are
+// built one at a time. Build failures are returned as a result so they reach
+// the caller like any other function error.
+func (l *Local) build(functionID string) (*runtime.BuildOutput, *lambda.Result) {
+	l.mu.Lock()
+	p := l.pool(functionID)
+	l.mu.Unlock()
+	p.build.Lock()
+	defer p.build.Unlock()
+
+	l.mu.Lock()
+	target := l.targets[functionID]
+	build, ok := l.builds[functionID]
+	l.mu.Unlock()
+	if ok {
+		return build, nil
+	}
+	build, err := l.runtimes.Build(l.ctx, target)
+	errors := []string{}
+	if err != nil {
+		errors = append(errors, err.Error())
+	} else {
+		errors = build.Errors
+	}
+	bus.Publish(&aws.FunctionBuildEvent{
+		FunctionID: functionID,
+		Errors:     errors,
+	})
+	if len(errors) > 0 {
+		return nil, &lambda.Result{
+			Error: &lambda.Error{
+				ErrorType:    "Runtime.BuildError",
+				ErrorMessage: strings.Join(errors, "\n"),
+			},
+		}
+	}
+	l.mu.Lock()
+	l.builds[functionID] = build
+	l.mu.Unlock()
+	return build, nil
+}
+
+// acquire takes an idle worker of the function or starts a new one. The most
+// recently used worker is picked so the others can be reaped.
+func (l *Local) acquire(functionID string) (*worker, *lambda.Result, error) {
+	l.mu.Lock()
+	p := l.pool(functionID)
+	if len(p.idle) > 0 {
+		w := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		l.publish(functionID)
+		l.mu.Unlock()
+		return w, nil, nil
+	}
+	l.mu.Unlock()
+
+	build, result := l.build(functionID)
+	if result != nil {
+		return nil, result, nil
+	}
+
+	// the caller holds a slot of the pool so the worker can be started without
+	// the lock, resolving the env can mean fetching credentials
+	l.mu.Lock()
+	target := l.targets[functionID]
+	w := &worker{
+		id:         "local-" + util.RandomString(8),
+		functionID: functionID,
+		done:       make(chan struct{}),
+		generation: p.generation,
+	}
+	env := slices.Clone(p.env)
+	memory := l.memory[functionID]
+	l.mu.Unlock()
+	l.api.Register(w.id, functionID)
+	if env == nil && l.env != nil {
+		env = l.env(target)
+	}
+	running, err := l.runtimes.Run(l.ctx, &runtime.RunInput{
+		CfgPath:    target.CfgPath,
+		Runtime:    target.Runtime,
+		Server:     l.server + "/" + w.id,
+		FunctionID: functionID,
+		WorkerID:   w.id,
+		Build:      build,
+		Env:        env,
+		Memory:     memory,
+	})
+	if err != nil {
+		l.api.Shutdown(w.id)
+		return nil, nil, err
+	}
+	w.worker = running
+	l.mu.Lock()
+	p.workers[w.id] = w
+	l.publish(functionID)
+	l.mu.Unlock()
+	go func() {
+		defer close(w.done)
+		scanner := bufio.NewScanner(running.Logs())
+		outOfMemory := false
+		for scanner.Scan() {
+			line := scanner.Text()
+			outOfMemory = outOfMemory || runtime.OutOfMemory(line)
+			requestID, _ := w.requestID.Load().(string)
+			bus.Publish(&aws.FunctionLogEvent{
+				FunctionID: functionID,
+				WorkerID:   w.id,
+				RequestID:  requestID,
+				Line:       line,
+			})
+		}
+		slog.Info("local worker exited", "workerID", w.id, "functionID", functionID)
+		if exit := runtime.Exited(running, outOfMemory); exit != nil {
+			l.api.Exit(w.id, &lambda.Error{
+				ErrorType:    exit.Type,
+				ErrorMessage: exit.Message,
+			})
+		}
+		l.api.Shutdown(w.id)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := p.workers[w.id]; ok {
+			delete(p.workers, w.id)
+			p.idle = slices.DeleteFunc(p.idle, func(item *worker) bool { return item == w })
+			l.publish(functionID)
+		}
+	}()
+	return w, nil, nil
+}
+
+var ErrFunctionNotFound = fmt.Errorf("function not found")
+
+// handleInvoke invokes a function with the request body, like the Lambda
+// Invoke API. Function errors are returned with the X-Amz-Function-Error
+// header.
+func (l *Local) handleInvoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	functionID := strings.TrimPrefix(r.URL.Path, "/local/invoke/")
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	result, err := l.Invoke(r.Context(), functionID, payload)
+	writeResult(w, result, err)
+}
+
+// handleReplay invokes a function again with the input of a recorded
+// invocation, against the current code.
+func (l *Local) handleReplay(store *recorder.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		invocation, err := store.Get(strings.TrimPrefix(r.URL.Path, "/local/replay/"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		result, err := l.Invoke(r.Context(), invocation.FunctionID, invocation.Input)
+		writeResult(w, result, err)
+	}
+}
+
+func writeResult(w http.ResponseWriter, result *lambda.Result, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrFunctionNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amz-Request-Id", result.RequestID)
+	if result.Error != nil {
+		w.Header().Set("X-Amz-Function-Error", "Unhandled")
+		json.NewEncoder(w).Encode(result.Error)
+		return
+	}
+	w.Write(result.Output)
+}
+
+// handleURL invokes a function with an HTTP request, like a function URL at
+// /local/url/{function}/{path}.
+func (l *Local) handleURL(w http.ResponseWriter, r *http.Request) {
+	functionID, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/local/url/"), "/")
+	payload, err := lambda.HTTPEvent(r, "/"+path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := l.Invoke(r.Context(), functionID, payload)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrFunctionNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if result.Error != nil {
+		http.Error(w, "Internal Server Error", http.StatusBadGateway)
+		return
+	}
+	lambda.WriteHTTPResponse(w, result.Output)
+}
+
+// Setup creates the Local of a project and serves its Runtime API on the dev
+// server, along with endpoints to invoke the functions that are registered in
+// dev.
+func Setup(ctx context.Context, p *project.Project, s *server.Server) *Local {
+	local := New(
+		ctx,
+		p.Runtime,
+		fmt.Sprintf("localhost:%d/local/runtime", s.Port),
+		func(target *runtime.BuildInput) []string {
+			return append(os.Environ(), p.TargetEnv(ctx, target)...)
+		},
+	)
+	s.Mux.Handle("/local/runtime/", http.StripPrefix("/local/runtime", local.Handler()))
+	s.Mux.HandleFunc("/local/invoke/", local.handleInvoke)
+	s.Mux.HandleFunc("/local/url/", local.handleURL)
+	s.Mux.HandleFunc("/local/replay/", local.handleReplay(recorder.Open(p)))
+	return local
+}
+
+// Start keeps the functions and their workers up to date until ctx is done.
+func (l *Local) Start(ctx context.Context, p *project.Project) error {
+	store := recorder.Open(p)
+	reap := time.NewTicker(l.idleTimeout / 5)
+	defer reap.Stop()
+	evts := bus.Subscribe(&runtime.BuildInput{}, &watcher.FileChangedEvent{}, &project.CompleteEvent{}, &recorder.ReplayEvent{})
+	for {
+		select {
+		case <-ctx.Done():
+			l.Stop()
+			return nil
+		case <-reap.C:
+			l.Reap()
+		case unknown := <-evts:
+			switch evt := unknown.(type) {
+			case *runtime.BuildInput:
+				l.AddTarget(evt)
+			case *watcher.FileChangedEvent:
+				l.FileChanged(evt.Paths()...)
+			case *recorder.ReplayEvent:
+				invocation, err := store.Get(evt.RequestID)
+				if err != nil {
+					slog.Error("failed to replay invocation", "requestID", evt.RequestID, "error", err)
+					continue
+				}
+				go l.Invoke(ctx, invocation.FunctionID, invocation.Input)
+			case *project.CompleteEvent:
+				for _, functionID := range project.Functions(evt) {
+					fn, err := p.LocalFunction(evt, functionID)
+					if err != nil {
+						continue
+					}
+					l.SetTimeout(functionID, fn.Timeout)
+					l.SetMemory(functionID, fn.Memory)
+				}
+			}
+		}
+	}
+}
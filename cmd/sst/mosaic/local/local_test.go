@@ -0,0 +1,307 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sst/ion/pkg/runtime"
+)
+
+// echoRuntime runs workers as goroutines that poll the Runtime API and echo
+// the payload back along with their worker ID.
+type echoRuntime struct {
+	builds  atomic.Int32
+	runs    atomic.Int32
+	release chan struct{}
+}
+
+type echoWorker struct {
+	cancel context.CancelFunc
+	logs   *io.PipeReader
+}
+
+func (w *echoWorker) Stop()               { w.cancel() }
+func (w *echoWorker) Logs() io.ReadCloser { return w.logs }
+
+func (r *echoRuntime) Match(runtime string) bool { return runtime == "echo" }
+
+func (r *echoRuntime) Build(ctx context.Context, input *runtime.BuildInput) (*runtime.BuildOutput, error) {
+	r.builds.Add(1)
+	return &runtime.BuildOutput{Handler: input.Handler, Errors: []string{}}, nil
+}
+
+func (r *echoRuntime) ShouldRebuild(functionID string, path string) bool {
+	return strings.HasSuffix(path, ".ts")
+}
+
+func (r *echoRuntime) Run(ctx context.Context, input *runtime.RunInput) (runtime.Worker, error) {
+	r.runs.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	read, write := io.Pipe()
+	go func() {
+		defer write.Close()
+		base := "http://" + input.Server + "/runtime/invocation/"
+		for {
+			req, _ := http.NewRequestWithContext(ctx, "GET", base+"next", nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			requestID := resp.Header.Get("Lambda-Runtime-Aws-Request-Id")
+			fmt.Fprintln(write, "handling", requestID)
+			if string(body) == `"sleep"` {
+				<-ctx.Done()
+				return
+			}
+			if string(body) == `"wait"` {
+				<-r.release
+			}
+			output, _ := json.Marshal(map[string]interface{}{
+				"worker": input.WorkerID,
+				"input":  json.RawMessage(body),
+			})
+			http.Post(base+requestID+"/response", "application/json", strings.NewReader(string(output)))
+		}
+	}()
+	return &echoWorker{cancel: cancel, logs: read}, nil
+}
+
+func setup(t *testing.T) (*Local, *echoRuntime) {
+	echo := &echoRuntime{release: make(chan struct{})}
+	cfgPath := filepath.Join(t.TempDir(), "sst.config.ts")
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	local := New(ctx, runtime.NewCollection(cfgPath, echo), strings.TrimPrefix(server.URL, "http://")+"/local/runtime", nil)
+	mux.Handle("/local/runtime/", http.StripPrefix("/local/runtime", local.Handler()))
+	mux.HandleFunc("/local/url/", local.handleURL)
+	t.Cleanup(func() {
+		local.Stop()
+		cancel()
+	})
+	target := &runtime.BuildInput{FunctionID: "MyFunction", Handler: "index.handler", Runtime: "echo"}
+	target.CfgPath = cfgPath
+	local.AddTarget(target)
+	return local, echo
+}
+
+func invoke(t *testing.T, local *Local, payload string) map[string]interface{} {
+	result, err := local.Invoke(context.Background(), "MyFunction", []byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error != nil {
+		t.Fatalf("unexpected error %+v", result.Error)
+	}
+	var output map[string]interface{}
+	json.Unmarshal(result.Output, &output)
+	return output
+}
+
+func TestLocalReusesWorker(t *testing.T) {
+	local, echo := setup(t)
+	first := invoke(t, local, `{"id":1}`)
+	second := invoke(t, local, `{"id":2}`)
+	if first["worker"] != second["worker"] {
+		t.Fatalf("expected the worker to be reused, got %v and %v", first["worker"], second["worker"])
+	}
+	if echo.builds.Load() != 1 || echo.runs.Load() != 1 {
+		t.Fatalf("expected one build and run, got %v and %v", echo.builds.Load(), echo.runs.Load())
+	}
+
+	local.FileChanged("/app/README.md")
+	invoke(t, local, `{}`)
+	if echo.builds.Load() != 1 {
+		t.Fatalf("expected unrelated change to not rebuild")
+	}
+
+	local.FileChanged("/app/src/index.ts")
+	third := invoke(t, local, `{}`)
+	if echo.builds.Load() != 2 || third["worker"] == first["worker"] {
+		t.Fatalf("expected a rebuild and a new worker")
+	}
+}
+
+func TestLocalTimeout(t *testing.T) {
+	local, echo := setup(t)
+	local.SetTimeout("MyFunction", 50*time.Millisecond)
+	result, err := local.Invoke(context.Background(), "MyFunction", []byte(`"sleep"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == nil || result.Error.ErrorType != "Sandbox.Timedout" {
+		t.Fatalf("expected timeout, got %+v", result)
+	}
+	invoke(t, local, `{}`)
+	if echo.runs.Load() != 2 {
+		t.Fatalf("expected a new worker after a timeout, got %v runs", echo.runs.Load())
+	}
+}
+
+func TestLocalUnknownFunction(t *testing.T) {
+	local, _ := setup(t)
+	_, err := local.Invoke(context.Background(), "Missing", []byte(`{}`))
+	if err != ErrFunctionNotFound {
+		t.Fatalf("expected ErrFunctionNotFound, got %v", err)
+	}
+}
+
+func TestLocalURL(t *testing.T) {
+	local, _ := setup(t)
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/local/url/MyFunction/users?id=1", strings.NewReader("hello"))
+	local.handleURL(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %v", recorder.Code)
+	}
+	var output struct {
+		Input struct {
+			RawPath string `json:"rawPath"`
+			Body    string `json:"body"`
+		} `json:"input"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &output)
+	if output.Input.RawPath != "/users" || output.Input.Body != "hello" {
+		t.Fatalf("unexpected event %s", recorder.Body.String())
+	}
+}
+
+func TestLocalConcurrency(t *testing.T) {
+	local, echo := setup(t)
+	local.concurrency = 2
+	done := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		go func() {
+			invoke(t, local, `"wait"`)
+			done <- struct{}{}
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		local.mu.Lock()
+		queued := local.pool("MyFunction").queued
+		local.mu.Unlock()
+		if echo.runs.Load() == 2 && queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 workers and 1 queued, got %v and %v", echo.runs.Load(), queued)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(echo.release)
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+	if echo.runs.Load() != 2 {
+		t.Fatalf("expected the queued invocation to reuse a worker, got %v runs", echo.runs.Load())
+	}
+}
+
+func TestLocalReap(t *testing.T) {
+	local, echo := setup(t)
+	invoke(t, local, `{}`)
+	local.reap(time.Now())
+	invoke(t, local, `{}`)
+	if echo.runs.Load() != 1 {
+		t.Fatalf("expected a recently used worker to be kept")
+	}
+	local.reap(time.Now().Add(DefaultIdleTimeout))
+	invoke(t, local, `{}`)
+	if echo.runs.Load() != 2 {
+		t.Fatalf("expected an idle worker to be reaped, got %v runs", echo.runs.Load())
+	}
+}
+
+func TestLocalSetEnv(t *testing.T) {
+	local, echo := setup(t)
+	invoke(t, local, `{}`)
+	local.SetEnv("MyFunction", []string{"FOO=bar", "AWS_SESSION_TOKEN=1"})
+	invoke(t, local, `{}`)
+	if echo.runs.Load() != 2 {
+		t.Fatalf("expected a new worker after the env changed, got %v runs", echo.runs.Load())
+	}
+	local.SetEnv("MyFunction", []string{"AWS_SESSION_TOKEN=2", "FOO=bar"})
+	invoke(t, local, `{}`)
+	if echo.runs.Load() != 2 {
+		t.Fatalf("expected new credentials to not restart the worker, got %v runs", echo.runs.Load())
+	}
+}
+
+func TestLocalEnvWithoutLock(t *testing.T) {
+	local, _ := setup(t)
+	resolving := make(chan struct{})
+	resolved := make(chan struct{})
+	local.env = func(target *runtime.BuildInput) []string {
+		close(resolving)
+		<-resolved
+		return []string{}
+	}
+	done := make(chan struct{})
+	go func() {
+		invoke(t, local, `{}`)
+		close(done)
+	}()
+	<-resolving
+	// fetching credentials for a new worker shouldn't hold up other invocations
+	if !local.mu.TryLock() {
+		t.Fatal("expected the lock to be released while the env is resolved")
+	}
+	local.mu.Unlock()
+	close(resolved)
+	<-done
+}
+
+func TestLocalCancelled(t *testing.T) {
+	local, echo := setup(t)
+	local.concurrency = 1
+	busy := make(chan struct{})
+	go func() {
+		invoke(t, local, `"wait"`)
+		close(busy)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for echo.runs.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the pool to fill up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// cancelled while waiting on the full pool
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := local.Invoke(ctx, "MyFunction", []byte(`{}`)); err != context.DeadlineExceeded {
+		t.Fatalf("expected the invocation to be cancelled, got %v", err)
+	}
+	close(echo.release)
+	<-busy
+
+	// cancelled before a free slot is taken, which the select can still pick
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 20; i++ {
+		local.Invoke(cancelled, "MyFunction", []byte(`{}`))
+	}
+	done := make(chan struct{})
+	go func() {
+		invoke(t, local, `{}`)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected cancelled invocations to release their slot")
+	}
+}
@@ -0,0 +1,290 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
+	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/runtime/lambda"
+)
+
+// Invocation is everything that happened during one invocation of a function
+// in dev, enough to replay it later.
+type Invocation struct {
+	RequestID  string          `json:"requestID"`
+	FunctionID string          `json:"functionID"`
+	WorkerID   string          `json:"workerID"`
+	Start      time.Time       `json:"start"`
+	End        time.Time       `json:"end"`
+	Input      json.RawMessage `json:"input"`
+	Output     json.RawMessage `json:"output,omitempty"`
+	Error      *lambda.Error   `json:"error,omitempty"`
+	Logs       []Log           `json:"logs"`
+}
+
+type Log struct {
+	Timestamp time.Time `json:"timestamp"`
+	Line      string    `json:"line"`
+}
+
+// ReplayEvent asks sst dev to invoke a function again with the input of a
+// recorded invocation.
+type ReplayEvent struct {
+	RequestID string
+}
+
+// DefaultMaxAge is how long invocations are kept, unless SST_LOG_RETENTION is
+// set in hours.
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+// DefaultMaxCount is how many invocations are kept per function.
+const DefaultMaxCount = 1000
+
+// Store keeps invocations as files in a directory, one per invocation,
+// grouped by function. Old invocations are pruned as new ones are saved.
+type Store struct {
+	dir      string
+	MaxAge   time.Duration
+	MaxCount int
+}
+
+func NewStore(dir string) *Store {
+	maxAge := DefaultMaxAge
+	if parsed, err := strconv.Atoi(flag.SST_LOG_RETENTION); err == nil && parsed > 0 {
+		maxAge = time.Duration(parsed) * time.Hour
+	}
+	return &Store{
+		dir:      dir,
+		MaxAge:   maxAge,
+		MaxCount: DefaultMaxCount,
+	}
+}
+
+// Open returns the store of the stage of a project.
+func Open(p *project.Project) *Store {
+	return NewStore(filepath.Join(p.PathWorkingDir(), "invocations", p.App().Stage))
+}
+
+func (s *Store) Save(invocation *Invocation) error {
+	data, err := json.MarshalIndent(invocation, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, invocation.FunctionID, invocation.RequestID+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return s.Prune(invocation.FunctionID, time.Now())
+}
+
+// Prune removes the invocations of a function, or of every function if
+// functionID is empty, that are older than MaxAge or past the most recent
+// MaxCount.
+func (s *Store) Prune(functionID string, now time.Time) error {
+	if functionID == "" {
+		dirs, err := os.ReadDir(s.dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, dir := range dirs {
+			if err := s.Prune(dir.Name(), now); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(s.dir, functionID, "*.json"))
+	if err != nil {
+		return err
+	}
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	files := []file{}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		files = append(files, file{match, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	for index, file := range files {
+		if index < s.MaxCount && now.Sub(file.modTime) < s.MaxAge {
+			continue
+		}
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+var ErrNotFound = fmt.Errorf("invocation not found")
+
+// Get looks up an invocation by its request ID.
+func (s *Store) Get(requestID string) (*Invocation, error) {
+	if requestID == "" || strings.ContainsAny(requestID, `/\*?[`) {
+		return nil, ErrNotFound
+	}
+	matches, _ := filepath.Glob(filepath.Join(s.dir, "*", requestID+".json"))
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return read(matches[0])
+}
+
+// List returns the invocations of a function, or of every function if
+// functionID is empty, most recent first.
+func (s *Store) List(functionID string) ([]*Invocation, error) {
+	if functionID == "" {
+		functionID = "*"
+	}
+	matches, err := filepath.Glob(filepath.Join(s.dir, functionID, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	result := []*Invocation{}
+	for _, match := range matches {
+		invocation, err := read(match)
+		if err != nil {
+			continue
+		}
+		result = append(result, invocation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.After(result[j].Start)
+	})
+	return result, nil
+}
+
+func read(path string) (*Invocation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var invocation Invocation
+	if err := json.Unmarshal(data, &invocation); err != nil {
+		return nil, err
+	}
+	return &invocation, nil
+}
+
+// raw keeps payloads that are not JSON as a JSON string so the invocation can
+// still be saved.
+func raw(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	encoded, _ := json.Marshal(string(data))
+	return encoded
+}
+
+// Recorder builds up invocations from the function events and saves them to
+// the store once they are done.
+type Recorder struct {
+	save    func(*Invocation) error
+	pending map[string]*Invocation
+}
+
+func New(store *Store) *Recorder {
+	return Collect(store.Save)
+}
+
+// Collect is like New but passes invocations to done instead of saving them.
+func Collect(done func(*Invocation) error) *Recorder {
+	return &Recorder{
+		save:    done,
+		pending: map[string]*Invocation{},
+	}
+}
+
+func (r *Recorder) Event(unknown interface{}) error {
+	switch evt := unknown.(type) {
+	case *aws.FunctionInvokedEvent:
+		r.pending[evt.RequestID] = &Invocation{
+			RequestID:  evt.RequestID,
+			FunctionID: evt.FunctionID,
+			WorkerID:   evt.WorkerID,
+			Start:      time.Now(),
+			Input:      raw(evt.Input),
+			Logs:       []Log{},
+		}
+	case *aws.FunctionLogEvent:
+		if invocation, ok := r.pending[evt.RequestID]; ok {
+			invocation.Logs = append(invocation.Logs, Log{
+				Timestamp: time.Now(),
+				Line:      ansi.Strip(evt.Line),
+			})
+		}
+	case *aws.FunctionResponseEvent:
+		if invocation, ok := r.pending[evt.RequestID]; ok {
+			invocation.Output = raw(evt.Output)
+			return r.done(invocation)
+		}
+	case *aws.FunctionErrorEvent:
+		if invocation, ok := r.pending[evt.RequestID]; ok {
+			invocation.Error = &lambda.Error{
+				ErrorType:    evt.ErrorType,
+				ErrorMessage: evt.ErrorMessage,
+				Trace:        evt.Trace,
+			}
+			return r.done(invocation)
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) done(invocation *Invocation) error {
+	delete(r.pending, invocation.RequestID)
+	invocation.End = time.Now()
+	return r.save(invocation)
+}
+
+// Start records every invocation of a function in dev.
+func Start(ctx context.Context, p *project.Project) error {
+	store := Open(p)
+	if err := store.Prune("", time.Now()); err != nil {
+		slog.Error("failed to prune invocations", "error", err)
+	}
+	recorder := New(store)
+	evts := bus.Subscribe(&aws.FunctionInvokedEvent{}, &aws.FunctionLogEvent{}, &aws.FunctionResponseEvent{}, &aws.FunctionErrorEvent{})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-evts:
+			if err := recorder.Event(evt); err != nil {
+				slog.Error("failed to record invocation", "error", err)
+			}
+		}
+	}
+}
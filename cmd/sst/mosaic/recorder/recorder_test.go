@@ -0,0 +1,97 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
+)
+
+func TestRecorder(t *testing.T) {
+	store := NewStore(t.TempDir())
+	recorder := New(store)
+	events := []interface{}{
+		&aws.FunctionInvokedEvent{FunctionID: "Consumer", WorkerID: "w1", RequestID: "first", Input: []byte(`{"Records":[{"body":"a"}]}`)},
+		&aws.FunctionLogEvent{FunctionID: "Consumer", WorkerID: "w1", RequestID: "first", Line: "\x1b[31mprocessing\x1b[0m"},
+		&aws.FunctionInvokedEvent{FunctionID: "Api", WorkerID: "w2", RequestID: "second", Input: []byte("not json")},
+		&aws.FunctionErrorEvent{FunctionID: "Consumer", WorkerID: "w1", RequestID: "first", ErrorType: "TypeError", ErrorMessage: "boom", Trace: []string{"at handler"}},
+		&aws.FunctionResponseEvent{FunctionID: "Api", WorkerID: "w2", RequestID: "second", Output: []byte(`{"statusCode":200}`)},
+		// logs after an invocation is done are dropped
+		&aws.FunctionLogEvent{FunctionID: "Consumer", WorkerID: "w1", RequestID: "first", Line: "late"},
+	}
+	for _, evt := range events {
+		if err := recorder.Event(evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := store.Get("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.FunctionID != "Consumer" || compact(first.Input) != `{"Records":[{"body":"a"}]}` {
+		t.Fatalf("unexpected invocation %+v", first)
+	}
+	if first.Error == nil || first.Error.ErrorType != "TypeError" || first.Output != nil {
+		t.Fatalf("expected an error, got %+v", first)
+	}
+	if len(first.Logs) != 1 || first.Logs[0].Line != "processing" {
+		t.Fatalf("unexpected logs %+v", first.Logs)
+	}
+
+	second, err := store.Get("second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(second.Input) != `"not json"` || compact(second.Output) != `{"statusCode":200}` {
+		t.Fatalf("unexpected invocation %+v", second)
+	}
+
+	all, err := store.List("")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected 2 invocations, got %v %v", len(all), err)
+	}
+	consumer, _ := store.List("Consumer")
+	if len(consumer) != 1 || consumer[0].RequestID != "first" {
+		t.Fatalf("unexpected invocations %+v", consumer)
+	}
+
+	for _, id := range []string{"missing", "*", "../first", ""} {
+		if _, err := store.Get(id); err != ErrNotFound {
+			t.Errorf("expected %q to not be found, got %v", id, err)
+		}
+	}
+}
+
+func compact(data []byte) string {
+	var buf bytes.Buffer
+	json.Compact(&buf, data)
+	return buf.String()
+}
+
+func TestStorePrune(t *testing.T) {
+	store := NewStore(t.TempDir())
+	store.MaxCount = 2
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.Save(&Invocation{RequestID: id, FunctionID: "Api", Input: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+		// mod times are how invocations are ordered
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := store.Get("a"); err != ErrNotFound {
+		t.Fatalf("expected the oldest invocation to be pruned, got %v", err)
+	}
+	if _, err := store.Get("c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Prune("", time.Now().Add(DefaultMaxAge)); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := store.List(""); len(list) != 0 {
+		t.Fatalf("expected old invocations to be pruned, got %v", len(list))
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/gorilla/websocket"
 	"github.com/sst/ion/cmd/sst/mosaic/aws"
+	"github.com/sst/ion/cmd/sst/mosaic/recorder"
 	"github.com/sst/ion/pkg/bus"
 	"github.com/sst/ion/pkg/project"
 	"github.com/sst/ion/pkg/server"
@@ -100,6 +101,13 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 				invocationClear <- source
 			}
 
+			if message["type"] == "invocation.replay" {
+				properties, _ := message["properties"].(map[string]interface{})
+				if id, ok := properties["id"].(string); ok {
+					bus.Publish(&recorder.ReplayEvent{RequestID: id})
+				}
+			}
+
 		}
 	})
 	sockets := make(map[*websocket.Conn]struct{})
@@ -123,6 +131,26 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 	}
 
 	var complete *project.CompleteEvent
+	source := func(functionID string) string {
+		if complete != nil {
+			for _, resource := range complete.Resources {
+				if resource.URN.Name() == functionID && resource.Type == "sst:aws:Function" {
+					return string(resource.URN)
+				}
+			}
+		}
+		return ""
+	}
+
+	// invocations from previous runs are loaded once the functions are known
+	// so they can be matched to their source
+	recorded, err := recorder.Open(p).List("")
+	if err != nil {
+		slog.Error("failed to load invocations", "error", err)
+	}
+	if len(recorded) > maxRecorded {
+		recorded = recorded[:maxRecorded]
+	}
 
 	for {
 		select {
@@ -144,19 +172,17 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 			switch evt := unknown.(type) {
 			case *project.CompleteEvent:
 				complete = evt
-				break
-			case *aws.FunctionInvokedEvent:
-				source := ""
-				if complete != nil {
-					for _, resource := range complete.Resources {
-						if resource.URN.Name() == evt.FunctionID && resource.Type == "sst:aws:Function" {
-							source = string(resource.URN)
-						}
+				for _, item := range recorded {
+					if _, ok := invocations[item.RequestID]; !ok {
+						invocations[item.RequestID] = fromRecorded(item, source(item.FunctionID))
 					}
 				}
+				recorded = nil
+				break
+			case *aws.FunctionInvokedEvent:
 				invocation := &Invocation{
 					ID:     evt.RequestID,
-					Source: source,
+					Source: source(evt.FunctionID),
 					Input:  json.RawMessage(evt.Input),
 					Start:  time.Now().UnixMilli(),
 					Errors: []InvocationError{},
@@ -238,3 +264,47 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 	}
 
 }
+
+// maxRecorded is how many invocations from previous runs are sent to the
+// console.
+const maxRecorded = 100
+
+func fromRecorded(item *recorder.Invocation, source string) *Invocation {
+	invocation := &Invocation{
+		ID:     item.RequestID,
+		Source: source,
+		Input:  item.Input,
+		Start:  item.Start.UnixMilli(),
+		End:    item.End.UnixMilli(),
+		Errors: []InvocationError{},
+		Logs:   []InvocationLog{},
+		Report: &InvocationReport{
+			Duration: item.End.Sub(item.Start).Milliseconds(),
+		},
+	}
+	if item.Output != nil {
+		invocation.Output = item.Output
+	}
+	for _, log := range item.Logs {
+		invocation.Logs = append(invocation.Logs, InvocationLog{
+			ID:        log.Timestamp.String(),
+			Timestamp: log.Timestamp.UnixMilli(),
+			Message:   log.Line,
+		})
+	}
+	if item.Error != nil {
+		error := InvocationError{
+			Message: item.Error.ErrorMessage,
+			Error:   item.Error.ErrorType,
+			Failed:  true,
+			Stack:   []Frame{},
+		}
+		for _, frame := range item.Error.Trace {
+			error.Stack = append(error.Stack, Frame{
+				Raw: frame,
+			})
+		}
+		invocation.Errors = append(invocation.Errors, error)
+	}
+	return invocation
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/ion/cmd/sst/mosaic/aws"
 	"github.com/sst/ion/cmd/sst/mosaic/deployer"
 	"github.com/sst/ion/pkg/project"
 	"golang.org/x/crypto/ssh/terminal"
@@ -28,8 +29,10 @@ type footer struct {
 	pending   []*apitype.ResourcePreEvent
 	skipped   int
 	cancelled bool
+	workers   map[string]*aws.FunctionWorkersEvent
 
 	spinner int
+	paused  bool
 
 	input chan any
 
@@ -42,7 +45,8 @@ type op struct {
 
 func NewFooter() *footer {
 	f := footer{
-		input: make(chan any),
+		input:   make(chan any),
+		workers: map[string]*aws.FunctionWorkersEvent{},
 	}
 	f.Reset()
 	return &f
@@ -75,12 +79,24 @@ func (m *footer) Start(ctx context.Context) {
 			}
 			width, _, _ := terminal.GetSize(int(os.Stdout.Fd()))
 			switch evt := val.(type) {
+			case pauseMsg:
+				m.paused = evt.paused
+				if m.paused {
+					m.clear()
+					os.Stdout.WriteString(ansi.ShowCursor)
+				} else {
+					os.Stdout.WriteString(ansi.HideCursor)
+				}
+				close(evt.done)
 			case lineMsg:
 				m.clear()
 				fmt.Println(evt)
 			default:
 				m.Update(val)
 			}
+			if m.paused {
+				continue
+			}
 			next := m.View(width)
 			m.Render(width, next)
 		}
@@ -195,6 +211,12 @@ func (m *footer) Update(msg any) {
 		if msg.Metadata.Op != apitype.OpSame && msg.Metadata.Op != apitype.OpRead {
 			m.pending = append(m.pending, msg)
 		}
+	case *aws.FunctionWorkersEvent:
+		if msg.Active == 0 && msg.Idle == 0 && msg.Queued == 0 {
+			delete(m.workers, msg.FunctionID)
+			break
+		}
+		m.workers[msg.FunctionID] = msg
 	case *apitype.SummaryEvent:
 		m.summary = true
 	case *apitype.ResOutputsEvent:
@@ -232,11 +254,14 @@ var TEXT_INFO = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
 var TEXT_INFO_BOLD = TEXT_INFO.Copy().Bold(true)
 
 func (m *footer) View(width int) string {
+	result := m.viewWorkers()
 	if !m.started || m.complete != nil {
-		return ""
+		if len(result) == 0 {
+			return ""
+		}
+		return lipgloss.NewStyle().MaxWidth(width).Render(lipgloss.JoinVertical(lipgloss.Top, result...))
 	}
 	spinner := spinner.MiniDot.Frames[m.spinner%len(spinner.MiniDot.Frames)]
-	result := []string{}
 	for _, r := range m.pending {
 		label := "Creating"
 		if r.Metadata.Op == apitype.OpUpdate {
@@ -282,6 +307,28 @@ func (m *footer) View(width int) string {
 	return lipgloss.NewStyle().MaxWidth(width).Render(lipgloss.JoinVertical(lipgloss.Top, result...))
 }
 
+// viewWorkers shows the local workers of the functions that have any running.
+func (m *footer) viewWorkers() []string {
+	functions := []string{}
+	for functionID := range m.workers {
+		functions = append(functions, functionID)
+	}
+	slices.Sort(functions)
+	result := []string{}
+	for _, functionID := range functions {
+		evt := m.workers[functionID]
+		line := fmt.Sprintf("λ  %-11s %s", functionID, TEXT_DIM.Render(fmt.Sprintf("%d/%d active", evt.Active, evt.Max)))
+		if evt.Idle > 0 {
+			line += TEXT_DIM.Render(fmt.Sprintf("  %d idle", evt.Idle))
+		}
+		if evt.Queued > 0 {
+			line += TEXT_WARNING.Render(fmt.Sprintf("  %d queued", evt.Queued))
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
 func (u *footer) removePending(urn string) {
 	next := []*apitype.ResourcePreEvent{}
 	for _, r := range u.pending {
@@ -325,3 +372,8 @@ func (u *footer) formatURN(urn string) string {
 }
 
 type lineMsg = string
+
+type pauseMsg struct {
+	paused bool
+	done   chan struct{}
+}
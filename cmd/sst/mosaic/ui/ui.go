@@ -21,6 +21,7 @@ import (
 	"github.com/sst/ion/cmd/sst/mosaic/deployer"
 	"github.com/sst/ion/cmd/sst/mosaic/ui/common"
 	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/runtime"
 
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -167,6 +168,9 @@ func (u *UI) Event(unknown interface{}) {
 		formattedDuration := fmt.Sprintf("%.9s", fmt.Sprintf("+%v", duration))
 		u.printEvent(u.getColor(evt.WorkerID), formattedDuration, evt.Line)
 
+	case *runtime.InspectEvent:
+		u.printEvent(u.getColor(evt.WorkerID), "Inspect", u.functionName(evt.FunctionID)+" "+TEXT_DIM.Render(evt.Debugger+" listening on "+evt.Address))
+
 	case *aws.FunctionBuildEvent:
 		if len(evt.Errors) > 0 {
 			u.printEvent(TEXT_DANGER, "Build Error", u.functionName(evt.FunctionID))
@@ -186,6 +190,9 @@ func (u *UI) Event(unknown interface{}) {
 			}
 			u.printEvent(u.getColor(evt.WorkerID), "", "↳ "+strings.TrimSpace(item))
 		}
+		if u.options.Dev && evt.RequestID != "" {
+			u.printEvent(u.getColor(evt.WorkerID), "", TEXT_DIM.Render("Replay with `sst invoke --replay "+evt.RequestID+"`"))
+		}
 
 	case *project.ConcurrentUpdateEvent:
 		u.reset()
@@ -229,6 +236,14 @@ func (u *UI) Event(unknown interface{}) {
 		}
 		u.blank()
 
+	case *project.TagPolicyEvent:
+		for _, status := range evt.Resources {
+			u.printEvent(TEXT_WARNING, "Tags", u.FormatURN(status.URN)+" is missing "+strings.Join(status.Missing, ", "))
+		}
+
+	case *project.HookEvent:
+		u.printEvent(TEXT_INFO, "Hook", evt.Name+" "+evt.Command)
+
 	case *project.BuildFailedEvent:
 		u.reset()
 		u.printEvent(TEXT_DANGER, "Error", evt.Error)
@@ -415,11 +430,18 @@ func (u *UI) Event(unknown interface{}) {
 				for _, line := range parseError(status.Message) {
 					u.println(TEXT_NORMAL.Render("   " + line))
 				}
+				helpStyle := TEXT_NORMAL
+				if status.Severity == "warning" {
+					helpStyle = TEXT_WARNING
+				}
+				if status.Severity == "info" {
+					helpStyle = TEXT_DIM
+				}
 				for i, line := range status.Help {
 					if i == 0 {
 						u.println()
 					}
-					u.println(TEXT_NORMAL.Render("   " + line))
+					u.println(helpStyle.Render("   " + line))
 				}
 				importDiffs, ok := evt.ImportDiffs[status.URN]
 				if ok {
@@ -543,6 +565,25 @@ func (u *UI) printEvent(barColor lipgloss.Style, label string, message ...string
 	}
 }
 
+// Pause stops rendering the footer so the terminal can be used for a prompt
+// until Resume is called.
+func (u *UI) Pause() {
+	u.setPaused(true)
+}
+
+func (u *UI) Resume() {
+	u.setPaused(false)
+}
+
+func (u *UI) setPaused(paused bool) {
+	if u.footer == nil {
+		return
+	}
+	done := make(chan struct{})
+	u.footer.Send(pauseMsg{paused: paused, done: done})
+	<-done
+}
+
 func (u *UI) Destroy() {
 	if u.footer != nil {
 		u.footer.Destroy()
@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ignore decides which paths the watcher skips. It follows the .gitignore
+// files in the tree, along with extra globs that use the same syntax.
+type ignore struct {
+	root  string
+	rules []rule
+}
+
+type rule struct {
+	base    string
+	source  string
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+func newIgnore(root string, globs []string) *ignore {
+	result := &ignore{root: root}
+	for _, glob := range globs {
+		if r, ok := parseRule(root, "", glob); ok {
+			result.rules = append(result.rules, r)
+		}
+	}
+	return result
+}
+
+// Load reads the .gitignore in dir, replacing the rules it had before.
+func (i *ignore) Load(dir string) error {
+	source := filepath.Join(dir, ".gitignore")
+	rules := []rule{}
+	for _, r := range i.rules {
+		if r.source != source {
+			rules = append(rules, r)
+		}
+	}
+	i.rules = rules
+	file, err := os.Open(source)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if r, ok := parseRule(dir, source, scanner.Text()); ok {
+			i.rules = append(i.rules, r)
+		}
+	}
+	// rules in deeper directories take precedence, like in git, and the
+	// configured globs over all of them
+	sort.SliceStable(i.rules, func(a, b int) bool {
+		return i.rules[a].rank() < i.rules[b].rank()
+	})
+	return scanner.Err()
+}
+
+func (r rule) rank() int {
+	if r.source == "" {
+		return math.MaxInt
+	}
+	return len(r.base)
+}
+
+// Match reports whether path should be skipped.
+func (i *ignore) Match(path string, dir bool) bool {
+	name := filepath.Base(path)
+	if dir && path != i.root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+		return true
+	}
+	ignored := false
+	for _, r := range i.rules {
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		match := r.pattern.FindStringSubmatch(filepath.ToSlash(rel))
+		if match == nil {
+			continue
+		}
+		// a pattern for directories also matches everything inside of them
+		if r.dirOnly && !dir && match[1] == "" {
+			continue
+		}
+		ignored = !r.negate
+	}
+	return ignored
+}
+
+func parseRule(base string, source string, line string) (rule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+	result := rule{base: base, source: source}
+	if strings.HasPrefix(line, "!") {
+		result.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		result.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+	prefix := "^(?:.*/)?"
+	if strings.Contains(line, "/") {
+		prefix = "^"
+		line = strings.TrimPrefix(line, "/")
+	}
+	pattern, err := regexp.Compile(prefix + globToRegexp(line) + "(/.*)?$")
+	if err != nil {
+		return rule{}, false
+	}
+	result.pattern = pattern
+	return result, true
+}
+
+func globToRegexp(glob string) string {
+	var result strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			result.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			result.WriteString(".*")
+			i++
+		case c == '*':
+			result.WriteString("[^/]*")
+		case c == '?':
+			result.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				result.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			result.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			result.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			result.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,55 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "packages", "web"), 0755)
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte("# build output\ndist/\n*.log\n!keep.log\n/coverage\n"), 0644)
+	os.WriteFile(filepath.Join(root, "packages", "web", ".gitignore"), []byte("generated/**/*.ts\n"), 0644)
+
+	ignore := newIgnore(root, []string{"**/*.tmp", "keep.log"})
+	if err := ignore.Load(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := ignore.Load(filepath.Join(root, "packages", "web")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		dir     bool
+		ignored bool
+	}{
+		{"src/index.ts", false, false},
+		{"dist", true, true},
+		{"dist/index.js", false, true},
+		{"packages/web/dist", true, true},
+		{"dist", false, false},
+		{"debug.log", false, true},
+		{"src/debug.log", false, true},
+		{"keep.log", false, true},
+		{"coverage", true, true},
+		{"src/coverage", true, false},
+		{"packages/web/generated/api/types.ts", false, true},
+		{"packages/web/generated/types.ts", false, true},
+		{"generated/types.ts", false, false},
+		{"src/cache.tmp", false, true},
+		{".git", true, true},
+		{".env", false, false},
+		{"node_modules", true, true},
+		{"packages/web/node_modules", true, true},
+	}
+	for _, test := range tests {
+		if ignore.Match(filepath.Join(root, test.path), test.dir) != test.ignored {
+			t.Errorf("expected %s ignored to be %v", test.path, test.ignored)
+		}
+	}
+	if ignore.Match(root, true) {
+		t.Error("expected root to be watched")
+	}
+}
@@ -2,6 +2,8 @@ package watcher
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -10,73 +12,243 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
 )
 
-type FileChangedEvent struct {
+// BatchDelay is how long the watcher waits for things to settle before it
+// publishes the changes it has seen, and MaxBatchDelay is the longest it waits
+// while files keep changing.
+const BatchDelay = 100 * time.Millisecond
+const MaxBatchDelay = time.Second
+
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpWrite  Op = "write"
+	OpRemove Op = "remove"
+	OpRename Op = "rename"
+)
+
+type Change struct {
 	Path string
+	Op   Op
+}
+
+// FileChangedEvent is published once for every batch of changes, so saving
+// many files at once or switching branches is handled in one go.
+type FileChangedEvent struct {
+	Changes []Change
+}
+
+func (e *FileChangedEvent) Paths() []string {
+	result := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		result[i] = change.Path
+	}
+	return result
 }
 
+type watcher struct {
+	root    string
+	fs      *fsnotify.Watcher
+	ignore  *ignore
+	dirs    map[string]bool
+	pending map[string]Op
+	order   []string
+	first   time.Time
+}
+
+// Start watches every directory under root that isn't ignored, including the
+// ones created later, until ctx is done. Besides the .gitignore files, paths
+// can be ignored with the globs in SST_WATCH_IGNORE.
 func Start(ctx context.Context, root string) error {
 	defer slog.Info("watcher done")
 	slog.Info("starting watcher", "root", root)
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return err
+	globs := []string{}
+	for _, glob := range strings.Split(flag.SST_WATCH_IGNORE, ",") {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			globs = append(globs, glob)
+		}
 	}
-	err = watcher.AddWith(root)
+	return watch(ctx, root, globs, func(evt *FileChangedEvent) {
+		bus.Publish(evt)
+	})
+}
+
+func watch(ctx context.Context, root string, globs []string, publish func(*FileChangedEvent)) error {
+	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
 	}
-	ignoreSubstrings := []string{"node_modules"}
-
-	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			if strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
-			}
-			for _, substring := range ignoreSubstrings {
-				if strings.Contains(path, substring) {
-					return filepath.SkipDir
-				}
-			}
-			slog.Info("watching", "path", path)
-			err = watcher.Add(path)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	})
-	if err != nil {
+	defer fsw.Close()
+	w := &watcher{
+		root:    root,
+		fs:      fsw,
+		ignore:  newIgnore(root, globs),
+		dirs:    map[string]bool{},
+		pending: map[string]Op{},
+	}
+	if _, err := w.add(root); err != nil {
 		return err
 	}
 
 	headFile := filepath.Join(root, ".git/HEAD")
-	watcher.Add(headFile)
-	limiter := map[string]time.Time{}
+	fsw.Add(headFile)
+	timer := time.NewTimer(BatchDelay)
+	timer.Stop()
 	for {
 		select {
-		case event, ok := <-watcher.Events:
+		case event, ok := <-fsw.Events:
 			if !ok {
 				return nil
 			}
 			if event.Name == headFile {
 				return nil
 			}
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
-				slog.Info("ignoring file event", "path", event.Name, "op", event.Op)
+			w.handle(event)
+			if len(w.pending) == 0 {
 				continue
 			}
-			slog.Info("file event", "path", event.Name, "op", event.Op)
-			if time.Since(limiter[event.Name]) > 500*time.Millisecond {
-				limiter[event.Name] = time.Now()
-				bus.Publish(&FileChangedEvent{Path: event.Name})
+			if time.Since(w.first) >= MaxBatchDelay {
+				publish(w.flush())
+				continue
+			}
+			timer.Reset(BatchDelay)
+		case <-timer.C:
+			if len(w.pending) > 0 {
+				publish(w.flush())
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
 			}
+			slog.Error("watcher error", "err", err)
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
+
+// add watches dir and the directories under it, and returns the files in them.
+func (w *watcher) add(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// it was removed while walking
+			if errors.Is(err, fs.ErrNotExist) && path != w.root {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			if !w.ignore.Match(path, false) {
+				files = append(files, path)
+			}
+			return nil
+		}
+		if w.ignore.Match(path, true) {
+			return filepath.SkipDir
+		}
+		if err := w.ignore.Load(path); err != nil {
+			slog.Error("failed to read .gitignore", "path", path, "err", err)
+		}
+		slog.Info("watching", "path", path)
+		if err := w.fs.Add(path); err != nil {
+			return err
+		}
+		w.dirs[path] = true
+		return nil
+	})
+	return files, err
+}
+
+// remove stops watching dir and the directories under it.
+func (w *watcher) remove(dir string) {
+	for path := range w.dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			w.fs.Remove(path)
+			delete(w.dirs, path)
+		}
+	}
+}
+
+func (w *watcher) handle(event fsnotify.Event) {
+	path := event.Name
+	switch {
+	case event.Has(fsnotify.Create):
+		info, err := os.Stat(path)
+		if err != nil {
+			return
+		}
+		if !info.IsDir() {
+			if !w.ignore.Match(path, false) {
+				w.record(path, OpCreate)
+			}
+			break
+		}
+		if w.ignore.Match(path, true) {
+			return
+		}
+		// files can be created before the directory is watched
+		files, err := w.add(path)
+		if err != nil {
+			slog.Error("failed to watch directory", "path", path, "err", err)
+		}
+		for _, file := range files {
+			w.record(file, OpCreate)
+		}
+	case event.Has(fsnotify.Write):
+		if w.ignore.Match(path, false) {
+			return
+		}
+		w.record(path, OpWrite)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		op := OpRemove
+		if event.Has(fsnotify.Rename) {
+			op = OpRename
+		}
+		dir := w.dirs[path]
+		if dir {
+			w.remove(path)
+		}
+		if w.ignore.Match(path, dir) {
+			return
+		}
+		w.record(path, op)
+	default:
+		slog.Info("ignoring file event", "path", path, "op", event.Op)
+		return
+	}
+	if filepath.Base(path) == ".gitignore" {
+		if err := w.ignore.Load(filepath.Dir(path)); err != nil {
+			slog.Error("failed to read .gitignore", "path", path, "err", err)
+		}
+	}
+}
+
+func (w *watcher) record(path string, op Op) {
+	slog.Info("file event", "path", path, "op", op)
+	previous, ok := w.pending[path]
+	if !ok {
+		if len(w.pending) == 0 {
+			w.first = time.Now()
+		}
+		w.order = append(w.order, path)
+	}
+	// a file that was created and then written to is still new
+	if previous == OpCreate && op == OpWrite {
+		return
+	}
+	w.pending[path] = op
+}
+
+func (w *watcher) flush() *FileChangedEvent {
+	result := &FileChangedEvent{}
+	for _, path := range w.order {
+		result.Changes = append(result.Changes, Change{Path: path, Op: w.pending[path]})
+	}
+	w.pending = map[string]Op{}
+	w.order = nil
+	return result
+}
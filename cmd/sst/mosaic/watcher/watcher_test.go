@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte("dist/\n"), 0644)
+	os.WriteFile(filepath.Join(root, "index.ts"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(root, "old.ts"), []byte("1"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *FileChangedEvent, 10)
+	go watch(ctx, root, []string{"*.tmp"}, func(evt *FileChangedEvent) {
+		events <- evt
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	next := func() []Change {
+		select {
+		case evt := <-events:
+			return evt.Changes
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for changes")
+			return nil
+		}
+	}
+
+	// everything saved at once arrives as one batch
+	os.WriteFile(filepath.Join(root, "index.ts"), []byte("2"), 0644)
+	os.WriteFile(filepath.Join(root, "cache.tmp"), []byte("2"), 0644)
+	os.Rename(filepath.Join(root, "old.ts"), filepath.Join(root, "new.ts"))
+	os.Remove(filepath.Join(root, "index.ts"))
+	changes := next()
+	expected := []Change{
+		{Path: filepath.Join(root, "index.ts"), Op: OpRemove},
+		{Path: filepath.Join(root, "old.ts"), Op: OpRename},
+		{Path: filepath.Join(root, "new.ts"), Op: OpCreate},
+	}
+	if !slices.Equal(changes, expected) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+
+	// directories created later are watched too
+	os.MkdirAll(filepath.Join(root, "src", "lib"), 0755)
+	os.MkdirAll(filepath.Join(root, "dist"), 0755)
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(filepath.Join(root, "dist", "index.js"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(root, "src", "lib", "util.ts"), []byte("1"), 0644)
+	found := false
+	for !found {
+		for _, change := range next() {
+			if filepath.Dir(change.Path) == filepath.Join(root, "dist") {
+				t.Fatalf("expected ignored directory to not be watched, got %v", change)
+			}
+			if change.Path == filepath.Join(root, "src", "lib", "util.ts") {
+				found = true
+			}
+		}
+	}
+}
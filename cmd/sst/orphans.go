@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+var CmdOrphans = &cli.Command{
+	Name: "orphans",
+	Description: cli.Description{
+		Short: "Find resources that are no longer in your app",
+		Long: strings.Join([]string{
+			"Finds the resources in your AWS account that are tagged with your app and stage but are no longer in its state.",
+			"",
+			"These are usually left behind by a remove that failed, resources that were retained, or stages that were removed.",
+			"",
+			"```bash frame=\"none\"",
+			"sst orphans --stage production",
+			"```",
+			"",
+			"It looks in the region of your `aws` provider and in `us-east-1`. Optionally, delete them.",
+			"",
+			"```bash frame=\"none\"",
+			"sst orphans --delete",
+			"```",
+			"",
+			"You'll be asked to confirm before they are deleted. In CI, or any time there's no terminal, pass in `--yes` as well.",
+			"",
+			"Only some types of resources can be deleted, like buckets, functions, tables, queues, topics, log groups, and roles. The rest are listed so you can remove them manually.",
+			"",
+			":::caution",
+			"Deleted resources cannot be recovered.",
+			":::",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "delete",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Delete the orphaned resources",
+				Long:  "Delete the orphaned resources. You'll be asked to confirm first.",
+			},
+		},
+		{
+			Name: "yes",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Delete without asking to confirm",
+				Long:  "Delete the orphaned resources without asking to confirm. This is needed to delete them without a terminal.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if !errors.Is(err, provider.ErrStateNotFound) {
+				return err
+			}
+			// everything in a stage without state is an orphan
+			complete = &project.CompleteEvent{}
+		}
+
+		orphans, err := p.FindOrphans(c.Context, complete)
+		if err != nil {
+			return util.NewReadableError(err, err.Error())
+		}
+		if len(orphans) == 0 {
+			fmt.Println(ui.TEXT_SUCCESS_BOLD.Render(ui.IconCheck) + ui.TEXT_NORMAL_BOLD.Render("  No orphaned resources"))
+			return nil
+		}
+
+		rows := [][]string{{"Type", "Region", "ARN"}}
+		for _, orphan := range orphans {
+			rows = append(rows, []string{orphan.Type, orphan.Region, orphan.ARN})
+		}
+		printTable(rows, nil)
+
+		if !c.Bool("delete") {
+			return nil
+		}
+		if !c.Bool("yes") && !isInteractive() {
+			return util.NewReadableError(nil, "Cannot ask to confirm deleting the resources without a terminal. Pass in --yes to delete them without a prompt.")
+		}
+		if !c.Bool("yes") {
+			confirmed := false
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().Title(fmt.Sprintf(" Delete %v resources?", len(orphans))).Affirmative("Yes").Negative("No").Value(&confirmed),
+				),
+			).WithTheme(huh.ThemeCatppuccin()).Run()
+			if err != nil && !errors.Is(err, huh.ErrUserAborted) {
+				return err
+			}
+			if !confirmed {
+				return nil
+			}
+		}
+
+		fmt.Println()
+		failed := 0
+		for _, orphan := range orphans {
+			err := p.DeleteOrphan(c.Context, orphan)
+			if err != nil {
+				failed++
+				fmt.Println(ui.TEXT_DANGER_BOLD.Render(ui.IconX) + "  " + ui.TEXT_NORMAL.Render(orphan.ARN) + ui.TEXT_DIM.Render(" "+err.Error()))
+				continue
+			}
+			fmt.Println(ui.TEXT_SUCCESS_BOLD.Render(ui.IconCheck) + "  " + ui.TEXT_NORMAL.Render(orphan.ARN))
+		}
+		if failed > 0 {
+			return util.NewReadableError(nil, fmt.Sprintf("Could not delete %v resources", failed))
+		}
+		return nil
+	},
+}
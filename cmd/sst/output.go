@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project/provider"
+	"github.com/yalp/jsonpath"
+)
+
+var CmdOutput = &cli.Command{
+	Name: "output",
+	Description: cli.Description{
+		Short: "Print the outputs of your app",
+		Long: strings.Join([]string{
+			"Prints the outputs returned by the `run` function of your `sst.config.ts`, as they were last deployed.",
+			"",
+			"```bash frame=\"none\"",
+			"sst output --stage production",
+			"```",
+			"",
+			"Optionally, pass in a key to print a single output. Nested values can be queried with a path.",
+			"",
+			"```bash frame=\"none\"",
+			"API_URL=$(sst output api.url --stage production)",
+			"```",
+			"",
+			"The output can be formatted as `json`, `dotenv`, `shell`, or `raw`. By default, it prints `json` for all the outputs and `raw` for a single output.",
+			"",
+			"```bash frame=\"none\"",
+			"sst output --format dotenv > .env.outputs",
+			"eval \"$(sst output --format shell)\"",
+			"```",
+		}, "\n"),
+	},
+	Args: []cli.Argument{
+		{
+			Name: "key",
+			Description: cli.Description{
+				Short: "The output to print",
+				Long:  "The output to print. Nested values can be selected with a path like `api.url`.",
+			},
+		},
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "One of json, dotenv, shell, or raw",
+				Long:  "The format to print the outputs in. One of `json`, `dotenv`, `shell`, or `raw`.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst output api.url --stage production",
+			Description: cli.Description{
+				Short: "Print the url of the api in production",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		key := c.Positional(0)
+		format := c.String("format")
+		if format == "" {
+			format = "json"
+			if key != "" {
+				format = "raw"
+			}
+		}
+		if format != "json" && format != "dotenv" && format != "shell" && format != "raw" {
+			return util.NewReadableError(nil, "Invalid format \""+format+"\". Must be one of json, dotenv, shell, or raw.")
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return util.NewReadableError(err, "Stage \""+p.App().Stage+"\" has not been deployed")
+			}
+			return err
+		}
+
+		var value interface{} = complete.Outputs
+		if key != "" {
+			value, err = jsonpath.Read(complete.Outputs, "$."+key)
+			if err != nil || value == nil {
+				return util.NewReadableError(err, "Output \""+key+"\" not found")
+			}
+		}
+
+		result, err := formatOutput(key, value, format)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	},
+}
+
+var invalidEnvRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func formatOutput(key string, value interface{}, format string) (string, error) {
+	switch format {
+	case "json":
+		bytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(bytes), nil
+	case "raw":
+		return stringifyOutput(value), nil
+	}
+
+	entries := map[string]interface{}{}
+	if match, ok := value.(map[string]interface{}); ok {
+		entries = match
+	} else {
+		entries[key] = value
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{}
+	for _, name := range names {
+		envName := invalidEnvRegex.ReplaceAllString(name, "_")
+		envValue := stringifyOutput(entries[name])
+		if format == "shell" {
+			lines = append(lines, "export "+envName+"='"+strings.ReplaceAll(envValue, "'", `'\''`)+"'")
+			continue
+		}
+		quoted, _ := json.Marshal(envValue)
+		lines = append(lines, envName+"="+string(quoted))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func stringifyOutput(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	bytes, _ := json.Marshal(value)
+	return string(bytes)
+}
@@ -45,10 +45,12 @@ func CmdRemove(c *cli.Cli) error {
 	defer ui.Destroy()
 	defer c.Cancel()
 	err = p.Run(c.Context, &project.StackInput{
-		Command:    "remove",
-		Target:     target,
-		ServerPort: s.Port,
-		Verbose:    c.Bool("verbose"),
+		Command:        "remove",
+		Target:         target,
+		ServerPort:     s.Port,
+		Verbose:        c.Bool("verbose"),
+		AllowDestroy:   c.Bool("allow-destroy"),
+		ConfirmDestroy: confirmDestroy(ui, p.App().Stage),
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/project/provider"
+	"github.com/sst/ion/pkg/server"
+	"golang.org/x/sync/errgroup"
+)
+
+type stageInfo struct {
+	Name      string
+	Update    *provider.Update
+	Locked    bool
+	Resources int
+	Metadata  provider.Metadata
+}
+
+func (s stageInfo) updated() time.Time {
+	if s.Update == nil {
+		return time.Time{}
+	}
+	value := s.Update.TimeCompleted
+	if value == "" {
+		value = s.Update.TimeStarted
+	}
+	parsed, _ := time.Parse(time.RFC3339, value)
+	return parsed
+}
+
+func listStages(backend provider.Home, app string) ([]stageInfo, error) {
+	names, err := provider.ListStages(backend, app)
+	if err != nil {
+		return nil, err
+	}
+	stages := make([]stageInfo, len(names))
+	wg := errgroup.Group{}
+	wg.SetLimit(10)
+	for i, name := range names {
+		stages[i].Name = name
+		wg.Go(func() error {
+			update, err := provider.GetLastUpdate(backend, app, name)
+			if err != nil {
+				return err
+			}
+			stages[i].Update = update
+			stages[i].Locked, err = provider.IsLocked(backend, app, name)
+			if err != nil {
+				return err
+			}
+			stages[i].Resources, err = provider.GetResourceCount(backend, app, name)
+			if err != nil {
+				return err
+			}
+			stages[i].Metadata, err = provider.GetMetadata(backend, app, name)
+			return err
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].updated().After(stages[j].updated())
+	})
+	return stages, nil
+}
+
+var CmdStageList = &cli.Command{
+	Name: "list",
+	Description: cli.Description{
+		Short: "List all the stages of your app",
+		Long: strings.Join([]string{
+			"Lists all the stages of your app that are stored in your home.",
+			"",
+			"```bash frame=\"none\"",
+			"sst stage list",
+			"```",
+			"",
+			"For each stage it shows when it was last updated, the command and the version of the CLI that updated it, if it is currently locked, the number of resources in it, and when it expires if it was deployed with `--ttl`.",
+		}, "\n"),
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		stages, err := listStages(p.Backend(), p.App().Name)
+		if err != nil {
+			return util.NewReadableError(err, "Could not list stages")
+		}
+		if len(stages) == 0 {
+			return util.NewReadableError(nil, "No stages found")
+		}
+
+		rows := [][]string{{"Stage", "Updated", "Command", "Version", "Locked", "Resources", "Expires"}}
+		for _, stage := range stages {
+			row := []string{stage.Name, "-", "-", "-", "no", fmt.Sprint(stage.Resources), "-"}
+			if updated := stage.updated(); !updated.IsZero() {
+				row[1] = updated.Local().Format("2006-01-02 15:04")
+			}
+			if stage.Update != nil {
+				row[2] = stage.Update.Command
+				row[3] = stage.Update.Version
+			}
+			if stage.Locked {
+				row[4] = "yes"
+			}
+			if stage.Metadata.Expires != nil {
+				row[6] = stage.Metadata.Expires.Local().Format("2006-01-02 15:04")
+				if stage.Metadata.Expired() {
+					row[6] += " (expired)"
+				}
+			}
+			rows = append(rows, row)
+		}
+		printTable(rows, func(row []string) bool {
+			return row[0] == p.App().Stage
+		})
+		return nil
+	},
+}
+
+var CmdStageGC = &cli.Command{
+	Name: "gc",
+	Description: cli.Description{
+		Short: "Remove all the expired stages",
+		Long: strings.Join([]string{
+			"Removes every stage of your app that was deployed with `--ttl` and has expired.",
+			"",
+			"```bash frame=\"none\"",
+			"sst stage gc",
+			"```",
+			"",
+			"Each stage is removed just like `sst remove`, based on the `removal` setting in your `sst.config.ts` for that stage.",
+			"Once a stage has been removed, its secrets and passphrase are also deleted from your home.",
+		}, "\n"),
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		stages, err := listStages(p.Backend(), p.App().Name)
+		if err != nil {
+			return util.NewReadableError(err, "Could not list stages")
+		}
+		expired := expiredStages(stages)
+		if len(expired) == 0 {
+			ui.Success("No expired stages")
+			return nil
+		}
+
+		u := ui.New(c.Context)
+		defer u.Destroy()
+		events := bus.SubscribeAll()
+		defer close(events)
+		go func() {
+			for evt := range events {
+				u.Event(evt)
+			}
+		}()
+
+		failed := []string{}
+		for _, stage := range expired {
+			if stage.Locked {
+				failed = append(failed, stage.Name)
+				continue
+			}
+			err := removeExpiredStage(c.Context, p, stage.Name)
+			if err != nil {
+				if c.Context.Err() != nil {
+					return err
+				}
+				failed = append(failed, stage.Name)
+				continue
+			}
+		}
+		if len(failed) > 0 {
+			return util.NewReadableError(nil, "Could not remove stages: "+strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+func removeExpiredStage(ctx context.Context, current *project.Project, stage string) error {
+	p, err := project.New(&project.ProjectConfig{
+		Version: current.Version(),
+		Stage:   stage,
+		Config:  current.PathConfig(),
+	})
+	if err != nil {
+		return err
+	}
+	defer p.Cleanup()
+	err = p.LoadHome()
+	if err != nil {
+		return err
+	}
+
+	s, err := server.New()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var wg errgroup.Group
+	defer wg.Wait()
+	wg.Go(func() error {
+		return s.Start(ctx, p)
+	})
+	defer cancel()
+
+	err = p.Run(ctx, &project.StackInput{
+		Command:    "remove",
+		ServerPort: s.Port,
+	})
+	if err != nil {
+		return err
+	}
+	return purgeStage(p.Backend(), p.App().Name, stage)
+}
+
+func expiredStages(stages []stageInfo) []stageInfo {
+	expired := []stageInfo{}
+	for _, stage := range stages {
+		if stage.Metadata.Expired() {
+			expired = append(expired, stage)
+		}
+	}
+	return expired
+}
+
+// purgeStage deletes a removed stage from the home. It's kept if any of its
+// resources are left so it can be removed again.
+func purgeStage(backend provider.Home, app, stage string) error {
+	remaining, err := provider.GetResourceCount(backend, app, stage)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%v resources remain in stage %v", remaining, stage)
+	}
+	return provider.Purge(backend, app, stage)
+}
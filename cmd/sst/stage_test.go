@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/global"
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+// testHome is a local home for an app that only exists for the test.
+func testHome(t *testing.T) (provider.Home, string) {
+	app := "test-" + util.RandomString(8)
+	t.Cleanup(func() {
+		for _, key := range []string{"app", "metadata", "secret", "lock", "passphrase"} {
+			os.RemoveAll(filepath.Join(global.ConfigDir(), "state", key, app))
+		}
+	})
+	return provider.NewLocalHome(), app
+}
+
+func TestUpdateExpiry(t *testing.T) {
+	backend, app := testHome(t)
+	expires := func() *time.Time {
+		metadata, err := provider.GetMetadata(backend, app, "pr-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return metadata.Expires
+	}
+
+	if err := updateExpiry(backend, app, "pr-1", 0); err != nil || expires() != nil {
+		t.Fatalf("expected a stage deployed without a ttl to not expire, got %v", err)
+	}
+	if err := updateExpiry(backend, app, "pr-1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if result := expires(); result == nil || result.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("expected the stage to expire in an hour, got %v", result)
+	}
+	if err := updateExpiry(backend, app, "pr-1", 0); err != nil || expires() != nil {
+		t.Fatalf("expected a deploy without a ttl to clear the expiry, got %v", err)
+	}
+}
+
+func TestExpiredStages(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	stages := []stageInfo{
+		{Name: "production"},
+		{Name: "pr-1", Metadata: provider.Metadata{Expires: &past}},
+		{Name: "pr-2", Metadata: provider.Metadata{Expires: &future}},
+		{Name: "pr-3", Metadata: provider.Metadata{Expires: &past}, Locked: true},
+	}
+	expired := expiredStages(stages)
+	if len(expired) != 2 || expired[0].Name != "pr-1" || expired[1].Name != "pr-3" {
+		t.Fatalf("unexpected expired stages %v", expired)
+	}
+}
+
+func TestPurgeStage(t *testing.T) {
+	backend, app := testHome(t)
+	expires := time.Now().Add(-time.Minute)
+	state := func(stage string, resources string) {
+		path := filepath.Join(global.ConfigDir(), "state", "app", app, stage+".json")
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := os.WriteFile(path, []byte(`{"checkpoint":{"latest":{"resources":[`+resources+`]}}}`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := provider.PutMetadata(backend, app, stage, provider.Metadata{Expires: &expires}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state("pr-1", "")
+	state("pr-2", `{"urn":"urn:pulumi:pr-2::app::aws:s3/bucketV2:BucketV2::Bucket"}`)
+
+	if err := purgeStage(backend, app, "pr-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := purgeStage(backend, app, "pr-2"); err == nil {
+		t.Fatal("expected a stage with resources left to not be purged")
+	}
+	stages, err := listStages(backend, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stages) != 1 || stages[0].Name != "pr-2" || !stages[0].Metadata.Expired() {
+		t.Fatalf("expected only the removed stage to be purged, got %+v", stages)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sst/ion/cmd/sst/mosaic/ui"
+)
+
+// printTable prints the rows in aligned columns, the first row is the header.
+// Rows that match highlight are printed in bold.
+func printTable(rows [][]string, highlight func(row []string) bool) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for index, row := range rows {
+		cells := []string{}
+		for i, cell := range row {
+			cells = append(cells, fmt.Sprintf("%-*s", widths[i], cell))
+		}
+		line := strings.TrimRight(strings.Join(cells, "   "), " ")
+		if index == 0 {
+			fmt.Println(ui.TEXT_DIM.Render(line))
+			continue
+		}
+		if highlight != nil && highlight(row) {
+			fmt.Println(ui.TEXT_HIGHLIGHT_BOLD.Render(line))
+			continue
+		}
+		fmt.Println(ui.TEXT_NORMAL.Render(line))
+	}
+}
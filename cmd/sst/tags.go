@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/ion/cmd/sst/cli"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+var CmdTagsReport = &cli.Command{
+	Name: "report",
+	Description: cli.Description{
+		Short: "Check the required tags on your resources",
+		Long: strings.Join([]string{
+			"Lists every resource in the state of the stage and whether it carries each of the `requiredTags` in your `sst.config.ts`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst tags report --stage production",
+			"```",
+			"",
+			"Resources that don't support tags are marked with a `-`. Optionally, only list the resources that are missing tags.",
+			"",
+			"```bash frame=\"none\"",
+			"sst tags report --missing",
+			"```",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "missing",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Only list resources that are missing tags",
+				Long:  "Only list the resources that are missing one or more of the required tags.",
+			},
+		},
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Use json to print the report as JSON",
+				Long:  "Use `json` to print the report as JSON.",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		required := p.App().RequiredTags
+		if len(required) == 0 {
+			return util.NewReadableError(nil, "There are no required tags. Set `requiredTags` in your sst.config.ts.")
+		}
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return util.NewReadableError(err, "Stage \""+p.App().Stage+"\" has not been deployed")
+			}
+			return err
+		}
+
+		report := p.TagReport(complete)
+		if c.Bool("missing") {
+			filtered := report[:0]
+			for _, status := range report {
+				if len(status.Missing) > 0 {
+					filtered = append(filtered, status)
+				}
+			}
+			report = filtered
+		}
+
+		if c.String("format") == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		rows := [][]string{append([]string{"Resource", "Type"}, required...)}
+		for _, status := range report {
+			row := []string{resource.URN(status.URN).Name(), status.Type}
+			for _, tag := range required {
+				cell := "-"
+				if status.Taggable {
+					cell = "yes"
+					if _, ok := status.Tags[tag]; !ok {
+						cell = "no"
+					}
+				}
+				row = append(row, cell)
+			}
+			rows = append(rows, row)
+		}
+		printTable(rows, func(row []string) bool {
+			for _, cell := range row[2:] {
+				if cell == "no" {
+					return true
+				}
+			}
+			return false
+		})
+		return nil
+	},
+}
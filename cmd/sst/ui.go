@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/sst/ion/cmd/sst/cli"
@@ -12,11 +13,16 @@ import (
 	"github.com/sst/ion/cmd/sst/mosaic/dev"
 	"github.com/sst/ion/cmd/sst/mosaic/ui"
 	"github.com/sst/ion/cmd/sst/mosaic/ui/common"
+	"github.com/sst/ion/internal/util"
 	"github.com/sst/ion/pkg/project"
+	"github.com/sst/ion/pkg/runtime"
 	"github.com/sst/ion/pkg/server"
 )
 
 func CmdUI(c *cli.Cli) error {
+	if c.String("replay") != "" {
+		return replayUI(c, c.String("replay"))
+	}
 	url, err := server.Discover("", "")
 	if err != nil {
 		return err
@@ -46,6 +52,8 @@ func CmdUI(c *cli.Cli) error {
 			aws.FunctionErrorEvent{},
 			aws.FunctionLogEvent{},
 			aws.FunctionBuildEvent{},
+			aws.FunctionWorkersEvent{},
+			runtime.InspectEvent{},
 		)
 	}
 	if filter == "sst" || filter == "" {
@@ -90,3 +98,24 @@ func CmdUI(c *cli.Cli) error {
 		}
 	}
 }
+
+func replayUI(c *cli.Cli, path string) error {
+	speed := 1.0
+	if c.String("speed") != "" {
+		parsed, err := strconv.ParseFloat(c.String("speed"), 64)
+		if err != nil || parsed < 0 {
+			return util.NewReadableError(err, "Invalid speed \""+c.String("speed")+"\". Use a multiplier like 10, or 0 to replay instantly.")
+		}
+		speed = parsed
+	}
+	u := ui.New(c.Context)
+	defer u.Destroy()
+	err := project.Replay(c.Context, path, speed, u.Event)
+	if err != nil {
+		if c.Context.Err() != nil {
+			return nil
+		}
+		return util.NewReadableError(err, "Could not replay "+path)
+	}
+	return nil
+}
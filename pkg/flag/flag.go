@@ -5,6 +5,7 @@ import (
 )
 
 var SST_NO_CLEANUP = os.Getenv("SST_NO_CLEANUP") != ""
+var SST_NO_CONFIG_CACHE = os.Getenv("SST_NO_CONFIG_CACHE") != ""
 var SST_PASSPHRASE = os.Getenv("SST_PASSPHRASE")
 var SST_PULUMI_PATH = os.Getenv("SST_PULUMI_PATH")
 var SST_PRINT_LOGS = os.Getenv("SST_PRINT_LOGS") != ""
@@ -13,3 +14,10 @@ var SST_SKIP_DEPENDENCY_CHECK = os.Getenv("SST_SKIP_DEPENDENCY_CHECK") != ""
 var SST_TELEMETRY_DISABLED = os.Getenv("SST_TELEMETRY_DISABLED") == "1" || os.Getenv("DO_NOT_TRACK") == "1"
 var SST_BUN_VERSION = os.Getenv("SST_BUN_VERSION")
 var NO_BUN = os.Getenv("NO_BUN") != ""
+var SST_LIVE_RELAY = os.Getenv("SST_LIVE_RELAY")
+var SST_LIVE_RELAY_TOKEN = os.Getenv("SST_LIVE_RELAY_TOKEN")
+var SST_DEV_CONCURRENCY = os.Getenv("SST_DEV_CONCURRENCY")
+var SST_DEV_IDLE_TIMEOUT = os.Getenv("SST_DEV_IDLE_TIMEOUT")
+var SST_DEV_NO_MEMORY_LIMIT = os.Getenv("SST_DEV_NO_MEMORY_LIMIT") != ""
+var SST_LOG_RETENTION = os.Getenv("SST_LOG_RETENTION")
+var SST_WATCH_IGNORE = os.Getenv("SST_WATCH_IGNORE")
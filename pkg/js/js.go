@@ -1,6 +1,7 @@
 package js
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -20,6 +21,9 @@ type EvalOptions struct {
 	Banner  string
 	Inject  []string
 	Define  map[string]string
+	// InMemory skips writing the output files, they are only returned in the
+	// result.
+	InMemory bool
 }
 
 type PackageJson struct {
@@ -48,12 +52,56 @@ type Metafile struct {
 }
 
 func Build(input EvalOptions) (esbuild.BuildResult, error) {
+	options := buildOptions(input)
+	slog.Info("esbuild building", "out", options.Outfile)
+	result := esbuild.Build(options)
+	return finishBuild(input, result)
+}
+
+// Context keeps an esbuild context alive between builds so that rebuilds are
+// incremental. The context is recreated whenever the options change.
+type Context struct {
+	key string
+	ctx esbuild.BuildContext
+}
+
+func (c *Context) Build(input EvalOptions) (esbuild.BuildResult, error) {
+	options := buildOptions(input)
+	key, err := json.Marshal([]interface{}{input.Dir, input.Code, input.Globals, input.Banner, input.Inject, input.Define, options.Outfile, input.InMemory})
+	if err != nil {
+		return esbuild.BuildResult{}, err
+	}
+	if c.ctx != nil && c.key != string(key) {
+		c.Dispose()
+	}
+	if c.ctx == nil {
+		ctx, ctxErr := esbuild.Context(options)
+		if ctxErr != nil {
+			return esbuild.BuildResult{Errors: ctxErr.Errors}, fmt.Errorf("%s", FormatError(ctxErr.Errors))
+		}
+		c.ctx = ctx
+		c.key = string(key)
+	}
+	slog.Info("esbuild rebuilding", "out", options.Outfile)
+	result := c.ctx.Rebuild()
+	return finishBuild(input, result)
+}
+
+func (c *Context) Dispose() {
+	if c.ctx == nil {
+		return
+	}
+	c.ctx.Dispose()
+	c.ctx = nil
+	c.key = ""
+}
+
+func buildOptions(input EvalOptions) esbuild.BuildOptions {
 	outfile := input.Outfile
 	if outfile == "" {
 		outfile = filepath.Join(input.Dir, ".sst", "platform", fmt.Sprintf("sst.config.%v.mjs", time.Now().UnixMilli()))
 	}
-	slog.Info("esbuild building", "out", outfile)
-	result := esbuild.Build(esbuild.BuildOptions{
+	return esbuild.BuildOptions{
 		Banner: map[string]string{
 			"js": `
 import { createRequire as topLevelCreateRequire } from 'module';
@@ -112,17 +160,20 @@ const __dirname = topLevelFileUrlToPath(new topLevelURL(".", import.meta.url))
 		Define:   input.Define,
 		Inject:   input.Inject,
 		Outfile:  outfile,
-		Write:    true,
+		Write:    !input.InMemory,
 		Bundle:   true,
 		Metafile: true,
-	})
+	}
+}
+
+func finishBuild(input EvalOptions, result esbuild.BuildResult) (esbuild.BuildResult, error) {
 	if len(result.Errors) > 0 {
 		for _, err := range result.Errors {
 			slog.Error("esbuild error", "text", err.Text)
 		}
 		return result, fmt.Errorf("%s", FormatError(result.Errors))
 	}
-	slog.Info("esbuild built", "outfile", outfile)
+	slog.Info("esbuild built")
 
 	analysis := esbuild.AnalyzeMetafile(result.Metafile, esbuild.AnalyzeMetafileOptions{
 		Verbose: true,
@@ -0,0 +1,269 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	esbuild "github.com/evanw/esbuild/pkg/api"
+	"github.com/sst/ion/pkg/flag"
+	"github.com/sst/ion/pkg/js"
+)
+
+// configBuild is the compiled sst.config along with the files that went into
+// it.
+type configBuild struct {
+	Outfile string
+	Files   []string
+	// cached builds are kept around for the next run
+	cached bool
+}
+
+type configCacheEntry struct {
+	Key    string            `json:"key"`
+	Inputs map[string]string `json:"inputs"`
+}
+
+const configCacheSize = 10
+
+func (p *Project) PathConfigCache() string {
+	return filepath.Join(p.PathPlatformDir(), "cache", "config")
+}
+
+// buildConfig compiles the sst.config. The output is stored under a hash of
+// its inputs and the options, so if none of them changed since the last run
+// the previous output is reused. In dev the esbuild context is kept alive so
+// the builds that do happen are incremental.
+func (p *Project) buildConfig(dev bool, input js.EvalOptions) (*configBuild, error) {
+	if flag.SST_NO_CONFIG_CACHE {
+		input.Outfile = filepath.Join(p.PathPlatformDir(), fmt.Sprintf("sst.config.%v.mjs", time.Now().UnixMilli()))
+		result, err := js.Build(input)
+		if err != nil {
+			return nil, err
+		}
+		files, err := metafileInputs(result.Metafile)
+		if err != nil {
+			return nil, err
+		}
+		return &configBuild{
+			Outfile: input.Outfile,
+			Files:   files,
+		}, nil
+	}
+
+	optionsKey, err := hashJSON([]interface{}{input.Code, input.Globals, input.Banner, input.Inject, input.Define})
+	if err != nil {
+		return nil, err
+	}
+	dir := p.PathConfigCache()
+	manifest := filepath.Join(dir, optionsKey+".json")
+	if entry, ok := readConfigCache(manifest); ok {
+		outfile := filepath.Join(dir, entry.Key, "sst.config.mjs")
+		if _, err := os.Stat(outfile); err == nil && configCacheValid(entry) {
+			slog.Info("using cached config", "key", entry.Key)
+			now := time.Now()
+			os.Chtimes(manifest, now, now)
+			files := []string{}
+			for file := range entry.Inputs {
+				files = append(files, file)
+			}
+			return &configBuild{Outfile: outfile, Files: files, cached: true}, nil
+		}
+	}
+
+	input.Outfile = filepath.Join(dir, "build", "sst.config.mjs")
+	input.InMemory = true
+	var result esbuild.BuildResult
+	if dev {
+		if p.configContext == nil {
+			p.configContext = &js.Context{}
+		}
+		result, err = p.configContext.Build(input)
+	} else {
+		result, err = js.Build(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	files, err := metafileInputs(result.Metafile)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := configCacheEntry{Inputs: map[string]string{}}
+	for _, file := range files {
+		entry.Inputs[file] = hashFile(file)
+	}
+	entry.Key, err = hashJSON([]interface{}{optionsKey, entry.Inputs})
+	if err != nil {
+		return nil, err
+	}
+	outDir := filepath.Join(dir, entry.Key)
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range result.OutputFiles {
+		err = writeFileAtomic(filepath.Join(outDir, filepath.Base(file.Path)), file.Contents)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	err = writeFileAtomic(manifest, data)
+	if err != nil {
+		return nil, err
+	}
+	pruneConfigCache(dir)
+
+	return &configBuild{
+		Outfile: filepath.Join(outDir, "sst.config.mjs"),
+		Files:   files,
+		cached:  true,
+	}, nil
+}
+
+func (b *configBuild) cleanup() {
+	if b.cached || flag.SST_NO_CLEANUP {
+		return
+	}
+	os.Remove(b.Outfile)
+	os.Remove(b.Outfile + ".map")
+}
+
+func metafileInputs(metafile string) ([]string, error) {
+	var meta js.Metafile
+	err := json.Unmarshal([]byte(metafile), &meta)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for key := range meta.Inputs {
+		absPath, err := filepath.Abs(key)
+		if err != nil {
+			continue
+		}
+		files = append(files, absPath)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func readConfigCache(path string) (*configCacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry configCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key == "" {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func configCacheValid(entry *configCacheEntry) bool {
+	for file, hash := range entry.Inputs {
+		if hashFile(file) != hash {
+			slog.Info("config input changed", "file", file)
+			return false
+		}
+	}
+	return true
+}
+
+// keeps the most recently used entries along with their outputs and removes
+// everything else
+func pruneConfigCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type manifest struct {
+		path    string
+		key     string
+		modTime time.Time
+	}
+	manifests := []manifest{}
+	for _, item := range entries {
+		if item.IsDir() || !strings.HasSuffix(item.Name(), ".json") {
+			continue
+		}
+		info, err := item.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, item.Name())
+		entry, ok := readConfigCache(path)
+		if !ok {
+			os.Remove(path)
+			continue
+		}
+		manifests = append(manifests, manifest{path, entry.Key, info.ModTime()})
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].modTime.After(manifests[j].modTime)
+	})
+	keep := map[string]bool{"build": true}
+	for index, item := range manifests {
+		if index >= configCacheSize {
+			os.Remove(item.path)
+			continue
+		}
+		keep[item.key] = true
+	}
+	for _, item := range entries {
+		if !item.IsDir() || keep[item.Name()] {
+			continue
+		}
+		info, err := item.Info()
+		// another process could have just written this output
+		if err != nil || time.Since(info.ModTime()) < time.Hour {
+			continue
+		}
+		os.RemoveAll(filepath.Join(dir, item.Name()))
+	}
+}
+
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashJSON(input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
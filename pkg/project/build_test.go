@@ -0,0 +1,64 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sst/ion/pkg/js"
+)
+
+func TestBuildConfigCache(t *testing.T) {
+	root := t.TempDir()
+	mod := filepath.Join(root, "mod.ts")
+	write := func(content string) {
+		if err := os.WriteFile(mod, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`export const value: number = 1;`)
+	p := &Project{root: root}
+	defer p.Cleanup()
+	input := js.EvalOptions{
+		Dir:    root,
+		Code:   `import { value } from "./mod.ts"; console.log(value, $app);`,
+		Define: map[string]string{"$app": `"app"`},
+	}
+
+	for _, dev := range []bool{false, true} {
+		first, err := p.buildConfig(dev, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(first.Outfile); err != nil {
+			t.Fatal(err)
+		}
+		second, err := p.buildConfig(dev, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.Outfile != first.Outfile {
+			t.Fatalf("expected cached build %v, got %v", first.Outfile, second.Outfile)
+		}
+
+		write(`export const value: number = 2;`)
+		changed, err := p.buildConfig(dev, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed.Outfile == first.Outfile {
+			t.Fatal("expected a new build after the input changed")
+		}
+
+		input.Define = map[string]string{"$app": `"other"`}
+		defined, err := p.buildConfig(dev, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if defined.Outfile == changed.Outfile {
+			t.Fatal("expected a new build after the defines changed")
+		}
+		write(`export const value: number = 1;`)
+		input.Define = map[string]string{"$app": `"app"`}
+	}
+}
@@ -0,0 +1,241 @@
+package project
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// PriceRule is the monthly price of a type of resource. It is either a fixed
+// price, a price for each value of an input, or a price per unit of a numeric
+// input. Count is an input that multiplies the price.
+type PriceRule struct {
+	Type    string             `json:"type"`
+	Monthly float64            `json:"monthly,omitempty"`
+	Input   string             `json:"input,omitempty"`
+	Prices  map[string]float64 `json:"prices,omitempty"`
+	PerUnit float64            `json:"perUnit,omitempty"`
+	Count   string             `json:"count,omitempty"`
+	Note    string             `json:"note,omitempty"`
+}
+
+//go:embed pricing.json
+var pricingJSON []byte
+
+var PriceRules = func() []PriceRule {
+	var result []PriceRule
+	err := json.Unmarshal(pricingJSON, &result)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}()
+
+// CostResource is a resource in the state or in a preview along with the
+// inputs its price is based on.
+type CostResource struct {
+	URN    string
+	Type   string
+	Parent string
+	Inputs map[string]interface{}
+}
+
+type CostEstimate struct {
+	URN     string  `json:"urn"`
+	Type    string  `json:"type"`
+	Monthly float64 `json:"monthly"`
+	Priced  bool    `json:"priced"`
+}
+
+func ResolvePricingPath(cfgPath string) string {
+	return filepath.Join(filepath.Dir(cfgPath), "sst.pricing.json")
+}
+
+// PriceRules returns the rules in the sst.pricing.json file next to the config
+// along with the built in ones. A type in the file replaces the built in rules
+// for it.
+func (p *Project) PriceRules() ([]PriceRule, error) {
+	path := ResolvePricingPath(p.PathConfig())
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return PriceRules, nil
+		}
+		return nil, err
+	}
+	var local []PriceRule
+	err = json.Unmarshal(data, &local)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid pricing in %v: %w", path, err)
+	}
+	overridden := map[string]bool{}
+	for _, rule := range local {
+		overridden[rule.Type] = true
+	}
+	result := append([]PriceRule{}, local...)
+	for _, rule := range PriceRules {
+		if !overridden[rule.Type] {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+// EstimateCost adds up the rules that match the resource. Resources without a
+// matching rule are not priced, which usually means they are billed on usage.
+func EstimateCost(rules []PriceRule, resource CostResource) CostEstimate {
+	estimate := CostEstimate{
+		URN:  resource.URN,
+		Type: resource.Type,
+	}
+	for _, rule := range rules {
+		if rule.Type != resource.Type {
+			continue
+		}
+		price := rule.Monthly
+		if rule.Input != "" {
+			value, ok := resource.Inputs[rule.Input]
+			if !ok || value == nil {
+				continue
+			}
+			if rule.Prices != nil {
+				match, ok := rule.Prices[fmt.Sprint(value)]
+				if !ok {
+					continue
+				}
+				price = match
+			} else {
+				units, ok := toNumber(value)
+				if !ok {
+					continue
+				}
+				price = units * rule.PerUnit
+			}
+		}
+		if rule.Count != "" {
+			if count, ok := toNumber(resource.Inputs[rule.Count]); ok {
+				price *= count
+			}
+		}
+		estimate.Priced = true
+		estimate.Monthly += price
+	}
+	return estimate
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+// CostResources returns the resources in the state that can be priced.
+func CostResources(resources []apitype.ResourceV3) []CostResource {
+	result := []CostResource{}
+	for _, resource := range resources {
+		result = append(result, CostResource{
+			URN:    string(resource.URN),
+			Type:   string(resource.Type),
+			Parent: string(resource.Parent),
+			Inputs: resource.Inputs,
+		})
+	}
+	return result
+}
+
+// PlannedCostResources applies the steps of a preview to the resources in the
+// state, returning the resources as they would be after a deploy.
+func PlannedCostResources(current []CostResource, steps []apitype.StepEventMetadata) []CostResource {
+	byURN := map[string]CostResource{}
+	order := []string{}
+	for _, resource := range current {
+		byURN[resource.URN] = resource
+		order = append(order, resource.URN)
+	}
+	for _, step := range steps {
+		switch step.Op {
+		case apitype.OpDelete, apitype.OpDeleteReplaced, apitype.OpReadDiscard, apitype.OpDiscardReplaced:
+			delete(byURN, step.URN)
+		case apitype.OpCreate, apitype.OpUpdate, apitype.OpReplace, apitype.OpCreateReplacement, apitype.OpImport, apitype.OpImportReplacement:
+			if step.New == nil {
+				continue
+			}
+			if _, ok := byURN[step.URN]; !ok {
+				order = append(order, step.URN)
+			}
+			byURN[step.URN] = CostResource{
+				URN:    step.URN,
+				Type:   step.New.Type,
+				Parent: step.New.Parent,
+				Inputs: step.New.Inputs,
+			}
+		}
+	}
+	result := []CostResource{}
+	for _, urn := range order {
+		if resource, ok := byURN[urn]; ok {
+			result = append(result, resource)
+			delete(byURN, urn)
+		}
+	}
+	return result
+}
+
+type ComponentCost struct {
+	URN       string         `json:"urn"`
+	Monthly   float64        `json:"monthly"`
+	Resources []CostEstimate `json:"resources"`
+}
+
+// ComponentCosts estimates the resources and adds them up by the top level
+// component they belong to.
+func ComponentCosts(rules []PriceRule, resources []CostResource) []ComponentCost {
+	parents := map[string]string{}
+	for _, resource := range resources {
+		parents[resource.URN] = resource.Parent
+	}
+	component := func(urn string) string {
+		for {
+			parent, ok := parents[urn]
+			if !ok || parent == "" || strings.Contains(parent, "pulumi:pulumi:Stack") {
+				return urn
+			}
+			urn = parent
+		}
+	}
+	result := []ComponentCost{}
+	index := map[string]int{}
+	for _, resource := range resources {
+		estimate := EstimateCost(rules, resource)
+		if !estimate.Priced {
+			continue
+		}
+		urn := component(resource.URN)
+		i, ok := index[urn]
+		if !ok {
+			i = len(result)
+			index[urn] = i
+			result = append(result, ComponentCost{URN: urn})
+		}
+		result[i].Monthly += estimate.Monthly
+		result[i].Resources = append(result[i].Resources, estimate)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].URN < result[j].URN
+	})
+	return result
+}
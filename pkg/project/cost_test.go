@@ -0,0 +1,63 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource CostResource
+		monthly  float64
+		priced   bool
+	}{
+		{"fixed", CostResource{Type: "aws:ec2/natGateway:NatGateway"}, 32.85, true},
+		{"by input", CostResource{Type: "aws:rds/instance:Instance", Inputs: map[string]interface{}{"instanceClass": "db.t4g.micro", "allocatedStorage": float64(20)}}, 11.68 + 20*0.115, true},
+		{"per unit", CostResource{Type: "aws:ecs/taskDefinition:TaskDefinition", Inputs: map[string]interface{}{"cpu": "1024", "memory": "2048"}}, 1024*0.02886 + 2048*0.00317, true},
+		{"count", CostResource{Type: "aws:elasticache/replicationGroup:ReplicationGroup", Inputs: map[string]interface{}{"nodeType": "cache.t4g.micro", "numCacheClusters": float64(2)}}, 2 * 11.68, true},
+		{"unknown value", CostResource{Type: "aws:rds/instance:Instance", Inputs: map[string]interface{}{"instanceClass": "db.x9.huge"}}, 0, false},
+		{"usage based", CostResource{Type: "aws:lambda/function:Function"}, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			estimate := EstimateCost(PriceRules, test.resource)
+			if estimate.Priced != test.priced {
+				t.Fatalf("expected priced %v, got %v", test.priced, estimate.Priced)
+			}
+			if diff := estimate.Monthly - test.monthly; diff > 0.001 || diff < -0.001 {
+				t.Fatalf("expected %v, got %v", test.monthly, estimate.Monthly)
+			}
+		})
+	}
+}
+
+func TestComponentCostsDiff(t *testing.T) {
+	stack := "urn:pulumi:prod::app::pulumi:pulumi:Stack::app-prod"
+	vpc := "urn:pulumi:prod::app::sst:aws:Vpc::Vpc"
+	nat := "urn:pulumi:prod::app::sst:aws:Vpc$aws:ec2/natGateway:NatGateway::VpcNat"
+	db := "urn:pulumi:prod::app::aws:rds/instance:Instance::Database"
+	current := []CostResource{
+		{URN: vpc, Type: "sst:aws:Vpc", Parent: stack},
+		{URN: nat, Type: "aws:ec2/natGateway:NatGateway", Parent: vpc},
+		{URN: db, Type: "aws:rds/instance:Instance", Parent: stack, Inputs: map[string]interface{}{"instanceClass": "db.t4g.micro"}},
+	}
+	planned := PlannedCostResources(current, []apitype.StepEventMetadata{
+		{Op: apitype.OpDelete, URN: nat},
+		{Op: apitype.OpUpdate, URN: db, New: &apitype.StepEventStateMetadata{
+			Type:   "aws:rds/instance:Instance",
+			Parent: stack,
+			Inputs: map[string]interface{}{"instanceClass": "db.t4g.small"},
+		}},
+	})
+
+	before := ComponentCosts(PriceRules, current)
+	if len(before) != 2 || before[0].URN != db || before[1].URN != vpc {
+		t.Fatalf("unexpected components %+v", before)
+	}
+	after := ComponentCosts(PriceRules, planned)
+	if len(after) != 1 || after[0].URN != db || after[0].Monthly != 23.36 {
+		t.Fatalf("unexpected components %+v", after)
+	}
+}
@@ -5,30 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/sst/ion/pkg/project/provider"
 )
 
-func (p *Project) EnvFor(ctx context.Context, complete *CompleteEvent, name string) (map[string]string, error) {
+// EnvFor returns the environment of a dev process. The credentials of its role
+// are only assumed and included with withCredentials, the dev server hands them
+// out through its credentials endpoint instead.
+func (p *Project) EnvFor(ctx context.Context, complete *CompleteEvent, name string, withCredentials bool) (map[string]string, error) {
 	dev := complete.Devs[name]
 	env := map[string]string{}
-	if dev.Aws != nil && dev.Aws.Role != "" {
+	if credentials := p.CredentialsFor(complete, name); credentials != nil {
 		prov, _ := p.Provider("aws")
-		awsProvider := prov.(*provider.AwsProvider)
-		stsClient := sts.NewFromConfig(awsProvider.Config())
-		sessionName := "sst-dev"
-		result, err := stsClient.AssumeRole(ctx, &sts.AssumeRoleInput{
-			RoleArn:         &dev.Aws.Role,
-			RoleSessionName: &sessionName,
-			DurationSeconds: awssdk.Int32(3600),
-		})
-		if err == nil {
-			env["AWS_ACCESS_KEY_ID"] = *result.Credentials.AccessKeyId
-			env["AWS_SECRET_ACCESS_KEY"] = *result.Credentials.SecretAccessKey
-			env["AWS_SESSION_TOKEN"] = *result.Credentials.SessionToken
-			env["AWS_REGION"] = awsProvider.Config().Region
+		env["AWS_REGION"] = prov.(*provider.AwsProvider).Config().Region
+		if withCredentials {
+			creds, err := credentials.Retrieve(ctx)
+			if err == nil {
+				env["AWS_ACCESS_KEY_ID"] = creds.AccessKeyID
+				env["AWS_SECRET_ACCESS_KEY"] = creds.SecretAccessKey
+				env["AWS_SESSION_TOKEN"] = creds.SessionToken
+			}
 		}
 	}
 	slog.Info("dev", "links", dev.Links)
@@ -43,3 +43,51 @@ func (p *Project) EnvFor(ctx context.Context, complete *CompleteEvent, name stri
 	}
 	return env, nil
 }
+
+// CredentialsFor returns the credentials of the role a dev process assumes, or
+// nil if it doesn't have one. They are cached and refreshed before they expire.
+func (p *Project) CredentialsFor(complete *CompleteEvent, name string) awssdk.CredentialsProvider {
+	dev := complete.Devs[name]
+	if dev.Aws == nil || dev.Aws.Role == "" {
+		return nil
+	}
+	prov, ok := p.Provider("aws")
+	if !ok {
+		return nil
+	}
+	stsClient := sts.NewFromConfig(prov.(*provider.AwsProvider).Config())
+	return awssdk.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, dev.Aws.Role, func(options *stscreds.AssumeRoleOptions) {
+		options.RoleSessionName = "sst-dev"
+		options.Duration = time.Hour
+	}))
+}
+
+// LinkEnv returns the environment that gives a process access to every linked
+// resource, along with the credentials of the aws provider.
+func (p *Project) LinkEnv(ctx context.Context, complete *CompleteEvent) (map[string]string, error) {
+	env := map[string]string{}
+	for resource, value := range complete.Links {
+		jsonValue, err := json.Marshal(value.Properties)
+		if err != nil {
+			return nil, err
+		}
+		env[fmt.Sprintf("SST_RESOURCE_%s", resource)] = string(jsonValue)
+	}
+	env["SST_RESOURCE_App"] = fmt.Sprintf(`{"name": "%s", "stage": "%s" }`, p.App().Name, p.App().Stage)
+
+	aws, ok := p.Provider("aws")
+	if ok {
+		cfg := aws.(*provider.AwsProvider).Config()
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		env["AWS_ACCESS_KEY_ID"] = creds.AccessKeyID
+		env["AWS_SECRET_ACCESS_KEY"] = creds.SecretAccessKey
+		env["AWS_SESSION_TOKEN"] = creds.SessionToken
+		if cfg.Region != "" {
+			env["AWS_REGION"] = cfg.Region
+		}
+	}
+	return env, nil
+}
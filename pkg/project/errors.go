@@ -0,0 +1,180 @@
+package project
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// CommonError is a rule that attaches help to errors that match it. Each of
+// the matchers is a regular expression and all the ones that are set have to
+// match. Named groups in them can be used in the help as ${name}.
+type CommonError struct {
+	Code     string           `json:"code"`
+	Message  string           `json:"message"`
+	Severity string           `json:"severity,omitempty"`
+	Match    CommonErrorMatch `json:"match"`
+	Short    []string         `json:"short"`
+	Long     []string         `json:"long"`
+	Source   string           `json:"source,omitempty"`
+
+	message *regexp.Regexp
+	urn     *regexp.Regexp
+	kind    *regexp.Regexp
+}
+
+type CommonErrorMatch struct {
+	Message string `json:"message,omitempty"`
+	URN     string `json:"urn,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+//go:embed errors.json
+var commonErrorsJSON []byte
+
+var CommonErrors = func() []CommonError {
+	result, err := parseCommonErrors(commonErrorsJSON, "sst")
+	if err != nil {
+		panic(err)
+	}
+	return result
+}()
+
+var commonErrorSeverities = []string{"error", "warning", "info"}
+
+var commonErrorVariableRegex = regexp.MustCompile(`\$\{(\w+)\}`)
+
+func parseCommonErrors(data []byte, source string) ([]CommonError, error) {
+	var result []CommonError
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		rule := &result[i]
+		rule.Source = source
+		if rule.Severity == "" {
+			rule.Severity = "error"
+		}
+		if !slices.Contains(commonErrorSeverities, rule.Severity) {
+			return nil, fmt.Errorf("%v: severity must be one of %v", rule.Code, strings.Join(commonErrorSeverities, ", "))
+		}
+		if rule.Match.Message == "" && rule.Match.URN == "" && rule.Match.Type == "" {
+			rule.Match.Message = regexp.QuoteMeta(rule.Message)
+		}
+		for _, item := range []struct {
+			pattern string
+			out     **regexp.Regexp
+		}{
+			{rule.Match.Message, &rule.message},
+			{rule.Match.URN, &rule.urn},
+			{rule.Match.Type, &rule.kind},
+		} {
+			if item.pattern == "" {
+				continue
+			}
+			compiled, err := regexp.Compile(item.pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", rule.Code, err)
+			}
+			*item.out = compiled
+		}
+	}
+	return result, nil
+}
+
+func ResolveCommonErrorsPath(cfgPath string) string {
+	return filepath.Join(filepath.Dir(cfgPath), "sst.errors.json")
+}
+
+// CommonErrors returns the built in rules along with the ones defined in the
+// sst.errors.json file next to the config.
+func (p *Project) CommonErrors() ([]CommonError, error) {
+	return LoadCommonErrors(ResolveCommonErrorsPath(p.PathConfig()))
+}
+
+func LoadCommonErrors(path string) ([]CommonError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return CommonErrors, nil
+		}
+		return nil, err
+	}
+	local, err := parseCommonErrors(data, path)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid common error in %v: %w", path, err)
+	}
+	return append(local, CommonErrors...), nil
+}
+
+// Matches checks the rule against an error and returns the named groups that
+// were captured.
+func (rule *CommonError) Matches(message, urn string) (map[string]string, bool) {
+	variables := map[string]string{}
+	kind := ""
+	if resource.URN(urn).IsValid() {
+		kind = string(resource.URN(urn).Type())
+	}
+	for _, item := range []struct {
+		regex *regexp.Regexp
+		input string
+	}{
+		{rule.message, message},
+		{rule.urn, urn},
+		{rule.kind, kind},
+	} {
+		if item.regex == nil {
+			continue
+		}
+		match := item.regex.FindStringSubmatch(item.input)
+		if match == nil {
+			return nil, false
+		}
+		for index, name := range item.regex.SubexpNames() {
+			if name != "" {
+				variables[name] = match[index]
+			}
+		}
+	}
+	return variables, true
+}
+
+func (rule *CommonError) Help(variables map[string]string) []string {
+	result := []string{}
+	for _, line := range rule.Short {
+		result = append(result, commonErrorVariableRegex.ReplaceAllStringFunc(line, func(match string) string {
+			name := commonErrorVariableRegex.FindStringSubmatch(match)[1]
+			if value, ok := variables[name]; ok {
+				return value
+			}
+			return match
+		}))
+	}
+	return result
+}
+
+// matchCommonErrors returns the help from every rule that matches along with
+// the most severe severity among them.
+func matchCommonErrors(rules []CommonError, message, urn string) ([]string, string) {
+	help := []string{}
+	severity := ""
+	for i := range rules {
+		variables, ok := rules[i].Matches(message, urn)
+		if !ok {
+			continue
+		}
+		help = append(help, rules[i].Help(variables)...)
+		if severity == "" || slices.Index(commonErrorSeverities, rules[i].Severity) < slices.Index(commonErrorSeverities, severity) {
+			severity = rules[i].Severity
+		}
+	}
+	return help, severity
+}
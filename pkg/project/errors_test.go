@@ -0,0 +1,44 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommonErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sst.errors.json")
+	err := os.WriteFile(path, []byte(`[
+  {
+    "code": "MigrationLock",
+    "message": "Migration table is locked",
+    "severity": "warning",
+    "match": {
+      "message": "table (?P<table>\\w+) is locked",
+      "type": "^sst:aws:Function"
+    },
+    "short": ["Unlock ${table} and deploy again."]
+  }
+]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadCommonErrors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != len(CommonErrors)+1 || rules[0].Source != path {
+		t.Fatalf("expected local rule first, got %v rules", len(rules))
+	}
+
+	help, severity := matchCommonErrors(rules, "table users is locked", "urn:pulumi:dev::app::sst:aws:Function::Api")
+	if len(help) != 1 || help[0] != "Unlock users and deploy again." || severity != "warning" {
+		t.Fatalf("unexpected match %v %v", help, severity)
+	}
+
+	help, _ = matchCommonErrors(rules, "table users is locked", "urn:pulumi:dev::app::aws:s3/bucket:Bucket::Bucket")
+	if len(help) != 0 {
+		t.Fatalf("expected type not to match, got %v", help)
+	}
+}
@@ -0,0 +1,203 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/ion/internal/util"
+	"github.com/sst/ion/pkg/project/provider"
+	"github.com/sst/ion/pkg/runtime"
+)
+
+// LocalFunction is a function in the state of a stage along with what is
+// needed to build and run it locally.
+type LocalFunction struct {
+	Build   *runtime.BuildInput
+	Links   []string
+	Env     map[string]string
+	Timeout time.Duration
+	Memory  int
+}
+
+// Functions returns the names of the functions in the state.
+func Functions(complete *CompleteEvent) []string {
+	result := []string{}
+	for _, resource := range complete.Resources {
+		if resource.Type == "sst:aws:Function" {
+			result = append(result, resource.URN.Name())
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// LocalFunction looks up a function by name. Functions deployed in dev mode
+// carry everything needed to build them, otherwise the handler and settings
+// are read from the deployed function.
+func (p *Project) LocalFunction(complete *CompleteEvent, name string) (*LocalFunction, error) {
+	var component *apitype.ResourceV3
+	for index, resource := range complete.Resources {
+		if resource.Type == "sst:aws:Function" && resource.URN.Name() == name {
+			component = &complete.Resources[index]
+			break
+		}
+	}
+	if component == nil {
+		return nil, util.NewReadableError(nil, fmt.Sprintf("Function %q was not found. Use one of: %s", name, strings.Join(Functions(complete), ", ")))
+	}
+
+	result := &LocalFunction{
+		Build: &runtime.BuildInput{
+			CfgPath:    p.PathConfig(),
+			Dev:        true,
+			FunctionID: name,
+			Runtime:    "nodejs20.x",
+		},
+		Env:     map[string]string{},
+		Timeout: 3 * time.Second,
+		Memory:  1024,
+	}
+
+	for _, resource := range complete.Resources {
+		if resource.Type != "aws:lambda/function:Function" || resource.Parent != component.URN {
+			continue
+		}
+		if value, ok := resource.Inputs["runtime"].(string); ok && value != "" {
+			result.Build.Runtime = value
+		}
+		if value, ok := resource.Inputs["timeout"].(float64); ok && value > 0 {
+			result.Timeout = time.Duration(value) * time.Second
+		}
+		if value, ok := resource.Inputs["memorySize"].(float64); ok && value > 0 {
+			result.Memory = int(value)
+		}
+		if environment, ok := resource.Inputs["environment"].(map[string]interface{}); ok {
+			variables, _ := environment["variables"].(map[string]interface{})
+			for key, value := range variables {
+				// these belong to the deployed function or its live bridge
+				if strings.HasPrefix(key, "SST_") {
+					continue
+				}
+				if value, ok := value.(string); ok {
+					result.Env[key] = value
+				}
+			}
+		}
+	}
+
+	if metadata, ok := component.Outputs["_metadata"].(map[string]interface{}); ok {
+		result.Build.Handler, _ = metadata["handler"].(string)
+	}
+
+	if live, ok := component.Outputs["_live"].(map[string]interface{}); ok {
+		data, err := json.Marshal(live)
+		if err != nil {
+			return nil, err
+		}
+		var parsed struct {
+			Handler    string          `json:"handler"`
+			Bundle     string          `json:"bundle"`
+			Runtime    string          `json:"runtime"`
+			Links      []string        `json:"links"`
+			Properties json.RawMessage `json:"properties"`
+			CopyFiles  []struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			} `json:"copyFiles"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		result.Build.Handler = parsed.Handler
+		result.Build.Bundle = parsed.Bundle
+		result.Build.Properties = parsed.Properties
+		result.Build.CopyFiles = parsed.CopyFiles
+		if parsed.Runtime != "" {
+			result.Build.Runtime = parsed.Runtime
+		}
+		result.Links = parsed.Links
+	} else {
+		for name := range complete.Links {
+			result.Links = append(result.Links, name)
+		}
+		sort.Strings(result.Links)
+	}
+
+	if result.Build.Handler == "" {
+		return nil, util.NewReadableError(nil, fmt.Sprintf("Function %q does not have a handler that can be run locally", name))
+	}
+	return result, nil
+}
+
+// LocalFunctionEnv returns the environment a function runs with locally. It
+// has the function's links and environment along with the credentials of the
+// aws provider.
+func (p *Project) LocalFunctionEnv(ctx context.Context, complete *CompleteEvent, fn *LocalFunction) ([]string, error) {
+	all, err := p.LinkEnv(ctx, complete)
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]string{}
+	for key, value := range all {
+		if strings.HasPrefix(key, "SST_RESOURCE_") {
+			continue
+		}
+		env[key] = value
+	}
+	for _, name := range fn.Links {
+		if value, ok := all["SST_RESOURCE_"+name]; ok {
+			env["SST_RESOURCE_"+name] = value
+		}
+	}
+	env["SST_RESOURCE_App"] = all["SST_RESOURCE_App"]
+	for key, value := range fn.Env {
+		env[key] = value
+	}
+	env["AWS_LAMBDA_FUNCTION_NAME"] = fn.Build.FunctionID
+	env["AWS_LAMBDA_FUNCTION_VERSION"] = "$LATEST"
+	env["AWS_LAMBDA_FUNCTION_MEMORY_SIZE"] = fmt.Sprint(fn.Memory)
+
+	result := []string{}
+	for key, value := range env {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// TargetEnv returns the environment a function registered in dev runs with
+// locally. Its links come from the build input so no state is needed, and the
+// credentials of the aws provider are added when they can be loaded.
+func (p *Project) TargetEnv(ctx context.Context, target *runtime.BuildInput) []string {
+	env := map[string]string{}
+	for name, value := range target.Links {
+		env["SST_RESOURCE_"+name] = string(value)
+	}
+	env["SST_RESOURCE_App"] = fmt.Sprintf(`{"name": "%s", "stage": "%s" }`, p.App().Name, p.App().Stage)
+	if aws, ok := p.Provider("aws"); ok {
+		cfg := aws.(*provider.AwsProvider).Config()
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err == nil {
+			env["AWS_ACCESS_KEY_ID"] = creds.AccessKeyID
+			env["AWS_SECRET_ACCESS_KEY"] = creds.SecretAccessKey
+			env["AWS_SESSION_TOKEN"] = creds.SessionToken
+		}
+		if cfg.Region != "" {
+			env["AWS_REGION"] = cfg.Region
+		}
+	}
+	env["AWS_LAMBDA_FUNCTION_NAME"] = target.FunctionID
+	env["AWS_LAMBDA_FUNCTION_VERSION"] = "$LATEST"
+
+	result := []string{}
+	for key, value := range env {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
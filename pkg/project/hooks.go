@@ -0,0 +1,97 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sst/ion/cmd/sst/mosaic/ui/common"
+	"github.com/sst/ion/pkg/bus"
+)
+
+type AppHooks struct {
+	PreDeploy  string `json:"preDeploy,omitempty"`
+	PostDeploy string `json:"postDeploy,omitempty"`
+	OnFailure  string `json:"onFailure,omitempty"`
+	PreRemove  string `json:"preRemove,omitempty"`
+}
+
+type HookEvent struct {
+	Name    string
+	Command string
+}
+
+// runHook runs one of the hooks in the root of the app with the linked
+// resources and the outputs of the app in its environment. The output is
+// published line by line.
+func (p *Project) runHook(ctx context.Context, name string, command string, complete *CompleteEvent) error {
+	if command == "" {
+		return nil
+	}
+	slog.Info("running hook", "name", name, "command", command)
+	bus.Publish(&HookEvent{Name: name, Command: command})
+
+	env := map[string]string{}
+	for _, item := range os.Environ() {
+		key, value, _ := strings.Cut(item, "=")
+		env[key] = value
+	}
+	links, err := p.LinkEnv(ctx, complete)
+	if err != nil {
+		return err
+	}
+	if _, ok := p.Provider("aws"); ok {
+		delete(env, "AWS_PROFILE")
+	}
+	for key, value := range links {
+		env[key] = value
+	}
+	outputs, err := json.Marshal(complete.Outputs)
+	if err != nil {
+		return err
+	}
+	env["SST_OUTPUTS"] = string(outputs)
+	if len(complete.Errors) > 0 {
+		errs, err := json.Marshal(complete.Errors)
+		if err != nil {
+			return err
+		}
+		env["SST_ERRORS"] = string(errs)
+	}
+	env["SST_HOOK"] = name
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = p.PathRoot()
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			bus.Publish(&common.StdoutEvent{Line: scanner.Text()})
+		}
+		io.Copy(io.Discard, reader)
+	}()
+	err = cmd.Wait()
+	writer.Close()
+	<-done
+	if err != nil {
+		return fmt.Errorf("The %v hook failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	cloudcontroltypes "github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+// Orphan is a cloud resource that is tagged with the app and stage but is not
+// in the state, like the ones left behind by a failed remove or a retained
+// resource.
+type Orphan struct {
+	ARN    string            `json:"arn"`
+	Region string            `json:"region"`
+	Type   string            `json:"type"`
+	Tags   map[string]string `json:"tags"`
+}
+
+// orphanClients creates the AWS clients for a region. The endpoint can be
+// overridden to point them at a stand-in.
+type orphanClients struct {
+	config   aws.Config
+	endpoint string
+}
+
+func (c *orphanClients) tagging(region string) *resourcegroupstaggingapi.Client {
+	return resourcegroupstaggingapi.NewFromConfig(c.config, func(options *resourcegroupstaggingapi.Options) {
+		options.Region = region
+		if c.endpoint != "" {
+			options.BaseEndpoint = aws.String(c.endpoint)
+		}
+	})
+}
+
+func (c *orphanClients) cloudControl(region string) *cloudcontrol.Client {
+	return cloudcontrol.NewFromConfig(c.config, func(options *cloudcontrol.Options) {
+		options.Region = region
+		if c.endpoint != "" {
+			options.BaseEndpoint = aws.String(c.endpoint)
+		}
+	})
+}
+
+func getTaggedResources(ctx context.Context, client *resourcegroupstaggingapi.Client, tags map[string]string) ([]taggingtypes.ResourceTagMapping, error) {
+	filters := []taggingtypes.TagFilter{}
+	for key, value := range tags {
+		filters = append(filters, taggingtypes.TagFilter{Key: aws.String(key), Values: []string{value}})
+	}
+	result := []taggingtypes.ResourceTagMapping{}
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(client, &resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: filters,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page.ResourceTagMappingList...)
+	}
+	return result, nil
+}
+
+// FindOrphans lists the resources tagged with the app and stage in the region
+// of the aws provider and in us-east-1, and returns the ones that are not in
+// the state.
+func (p *Project) FindOrphans(ctx context.Context, complete *CompleteEvent) ([]Orphan, error) {
+	prov, ok := p.Provider("aws")
+	if !ok {
+		return nil, fmt.Errorf("Finding orphaned resources requires the aws provider")
+	}
+	return findOrphans(ctx, &orphanClients{config: prov.(*provider.AwsProvider).Config()}, p.app.Name, p.app.Stage, complete.Resources)
+}
+
+func findOrphans(ctx context.Context, clients *orphanClients, app, stage string, resources []apitype.ResourceV3) ([]Orphan, error) {
+	known := map[string]bool{}
+	for _, resource := range resources {
+		collectStrings(resource.Outputs, known)
+		collectStrings(resource.Inputs, known)
+		known[resource.ID.String()] = true
+	}
+
+	regions := []string{clients.config.Region}
+	if clients.config.Region != "us-east-1" {
+		regions = append(regions, "us-east-1")
+	}
+	seen := map[string]bool{}
+	result := []Orphan{}
+	for _, region := range regions {
+		tagged, err := getTaggedResources(ctx, clients.tagging(region), map[string]string{
+			"sst:app":   app,
+			"sst:stage": stage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range tagged {
+			resourceARN := aws.ToString(item.ResourceARN)
+			if seen[resourceARN] || known[resourceARN] {
+				continue
+			}
+			seen[resourceARN] = true
+			parsed, err := arn.Parse(resourceARN)
+			if err != nil {
+				slog.Info("skipping invalid arn", "arn", resourceARN)
+				continue
+			}
+			// some resources are referenced by their name or id in the state
+			_, id := splitResource(parsed.Resource)
+			if known[id] {
+				continue
+			}
+			orphan := Orphan{
+				ARN:    resourceARN,
+				Region: parsed.Region,
+				Type:   orphanType(parsed),
+				Tags:   map[string]string{},
+			}
+			for _, tag := range item.Tags {
+				orphan.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			result = append(result, orphan)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ARN < result[j].ARN
+	})
+	return result, nil
+}
+
+func collectStrings(input interface{}, out map[string]bool) {
+	switch value := input.(type) {
+	case string:
+		out[value] = true
+	case map[string]interface{}:
+		for _, item := range value {
+			collectStrings(item, out)
+		}
+	case []interface{}:
+		for _, item := range value {
+			collectStrings(item, out)
+		}
+	}
+}
+
+// splitResource splits the resource part of an ARN, like function:name or
+// table/name, into its type and id.
+func splitResource(resource string) (string, string) {
+	index := strings.IndexAny(resource, ":/")
+	if index == -1 {
+		return "", resource
+	}
+	return resource[:index], resource[index+1:]
+}
+
+func orphanType(parsed arn.ARN) string {
+	kind, _ := splitResource(parsed.Resource)
+	if kind == "" {
+		return parsed.Service
+	}
+	return parsed.Service + ":" + kind
+}
+
+// cloudControlTypes maps the type of an ARN to the Cloud Control type and a
+// function that returns the identifier it expects.
+var cloudControlTypes = map[string]struct {
+	TypeName   string
+	Identifier func(parsed arn.ARN) string
+}{
+	"s3":              {"AWS::S3::Bucket", func(parsed arn.ARN) string { return parsed.Resource }},
+	"lambda:function": {"AWS::Lambda::Function", resourceID},
+	"dynamodb:table":  {"AWS::DynamoDB::Table", resourceID},
+	"sns":             {"AWS::SNS::Topic", fullARN},
+	"sqs": {"AWS::SQS::Queue", func(parsed arn.ARN) string {
+		return fmt.Sprintf("https://sqs.%v.amazonaws.com/%v/%v", parsed.Region, parsed.AccountID, parsed.Resource)
+	}},
+	"logs:log-group":        {"AWS::Logs::LogGroup", func(parsed arn.ARN) string { return strings.TrimSuffix(resourceID(parsed), ":*") }},
+	"iam:role":              {"AWS::IAM::Role", resourceID},
+	"events:rule":           {"AWS::Events::Rule", fullARN},
+	"states:stateMachine":   {"AWS::StepFunctions::StateMachine", fullARN},
+	"secretsmanager:secret": {"AWS::SecretsManager::Secret", fullARN},
+	"kinesis:stream":        {"AWS::Kinesis::Stream", resourceID},
+	"ecr:repository":        {"AWS::ECR::Repository", resourceID},
+}
+
+func resourceID(parsed arn.ARN) string {
+	_, id := splitResource(parsed.Resource)
+	return id
+}
+
+func fullARN(parsed arn.ARN) string {
+	return parsed.String()
+}
+
+// DeleteOrphan deletes the resource through Cloud Control and waits for it to
+// be gone. Only some types of resources are supported.
+func (p *Project) DeleteOrphan(ctx context.Context, orphan Orphan) error {
+	prov, ok := p.Provider("aws")
+	if !ok {
+		return fmt.Errorf("Deleting orphaned resources requires the aws provider")
+	}
+	return deleteOrphan(ctx, &orphanClients{config: prov.(*provider.AwsProvider).Config()}, orphan, 2*time.Second)
+}
+
+func deleteOrphan(ctx context.Context, clients *orphanClients, orphan Orphan, interval time.Duration) error {
+	parsed, err := arn.Parse(orphan.ARN)
+	if err != nil {
+		return err
+	}
+	match, ok := cloudControlTypes[orphan.Type]
+	if !ok {
+		return fmt.Errorf("Deleting %v resources is not supported, remove it manually", orphan.Type)
+	}
+	client := clients.cloudControl(parsed.Region)
+	output, err := client.DeleteResource(ctx, &cloudcontrol.DeleteResourceInput{
+		TypeName:   aws.String(match.TypeName),
+		Identifier: aws.String(match.Identifier(parsed)),
+	})
+	if err != nil {
+		return err
+	}
+	progress := output.ProgressEvent
+	for {
+		if progress == nil {
+			return fmt.Errorf("Cloud Control did not return the status of deleting %v", orphan.ARN)
+		}
+		switch progress.OperationStatus {
+		case cloudcontroltypes.OperationStatusSuccess:
+			return nil
+		case cloudcontroltypes.OperationStatusFailed, cloudcontroltypes.OperationStatusCancelComplete:
+			return fmt.Errorf("%v", aws.ToString(progress.StatusMessage))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		status, err := client.GetResourceRequestStatus(ctx, &cloudcontrol.GetResourceRequestStatusInput{
+			RequestToken: progress.RequestToken,
+		})
+		if err != nil {
+			return err
+		}
+		progress = status.ProgressEvent
+	}
+}
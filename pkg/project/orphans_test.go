@@ -0,0 +1,76 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestOrphans(t *testing.T) {
+	deleted := []map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Error("expected request to be signed")
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch r.Header.Get("X-Amz-Target") {
+		case "ResourceGroupsTaggingAPI_20170126.GetResources":
+			var input struct{ PaginationToken string }
+			json.Unmarshal(body, &input)
+			if input.PaginationToken == "" {
+				w.Write([]byte(`{"PaginationToken":"next","ResourceTagMappingList":[
+					{"ResourceARN":"arn:aws:lambda:us-west-2:123:function:app-dev-Api","Tags":[{"Key":"sst:app","Value":"app"}]},
+					{"ResourceARN":"arn:aws:s3:::app-dev-assets"}
+				]}`))
+				return
+			}
+			w.Write([]byte(`{"ResourceTagMappingList":[{"ResourceARN":"arn:aws:dynamodb:us-west-2:123:table/app-dev-Table"}]}`))
+		case "CloudApiService.DeleteResource":
+			input := map[string]string{}
+			json.Unmarshal(body, &input)
+			deleted = append(deleted, input)
+			w.Write([]byte(`{"ProgressEvent":{"RequestToken":"token","OperationStatus":"IN_PROGRESS"}}`))
+		case "CloudApiService.GetResourceRequestStatus":
+			w.Write([]byte(`{"ProgressEvent":{"RequestToken":"token","OperationStatus":"SUCCESS"}}`))
+		default:
+			w.WriteHeader(400)
+			w.Write([]byte(`{"message":"unknown target"}`))
+		}
+	}))
+	defer server.Close()
+
+	clients := &orphanClients{
+		config: aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		},
+		endpoint: server.URL,
+	}
+
+	orphans, err := findOrphans(context.Background(), clients, "app", "dev", []apitype.ResourceV3{
+		{Outputs: map[string]interface{}{"arn": "arn:aws:lambda:us-west-2:123:function:app-dev-Api"}},
+		{ID: "app-dev-assets"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0].Type != "dynamodb:table" || orphans[0].Region != "us-west-2" {
+		t.Fatalf("unexpected orphans %+v", orphans)
+	}
+
+	err = deleteOrphan(context.Background(), clients, orphans[0], time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0]["TypeName"] != "AWS::DynamoDB::Table" || deleted[0]["Identifier"] != "app-dev-Table" {
+		t.Fatalf("unexpected delete %v", deleted)
+	}
+}
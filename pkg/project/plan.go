@@ -0,0 +1,154 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/ion/internal/util"
+)
+
+// PlannedChange is a resource operation that a deploy or remove is going to
+// make.
+type PlannedChange struct {
+	URN  string
+	Type string
+	Op   apitype.OpType
+}
+
+var destructiveOps = []apitype.OpType{
+	apitype.OpDelete,
+	apitype.OpReplace,
+	apitype.OpCreateReplacement,
+	apitype.OpDeleteReplaced,
+}
+
+func (c PlannedChange) Destructive() bool {
+	return slices.Contains(destructiveOps, c.Op)
+}
+
+// Plan is the result of a preview. Outputs have the detailed diff of every
+// resource that changes and Parents maps each resource to its component.
+type Plan struct {
+	Changes  []PlannedChange
+	Outputs  []*apitype.ResOutputsEvent
+	Parents  map[string]string
+	Untagged []TagStatus
+}
+
+var ErrPlanRejected = util.NewReadableError(nil, "Deploy cancelled")
+
+// previewPlan runs a preview without publishing any of its events.
+func previewPlan(ctx context.Context, stack auto.Stack, requiredTags []string, opts ...optpreview.Option) (*Plan, error) {
+	stream := make(chan events.EngineEvent)
+	plan := &Plan{
+		Changes:  []PlannedChange{},
+		Outputs:  []*apitype.ResOutputsEvent{},
+		Parents:  map[string]string{},
+		Untagged: []TagStatus{},
+	}
+	errs := []string{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range stream {
+			if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" {
+				errs = append(errs, strings.TrimSpace(event.DiagnosticEvent.Message))
+			}
+			if event.ResOutputsEvent != nil && event.ResOutputsEvent.Metadata.Op != apitype.OpSame {
+				plan.Outputs = append(plan.Outputs, event.ResOutputsEvent)
+			}
+			if event.ResourcePreEvent == nil {
+				continue
+			}
+			metadata := event.ResourcePreEvent.Metadata
+			if metadata.New != nil && metadata.New.Parent != "" {
+				plan.Parents[metadata.URN] = metadata.New.Parent
+			} else if metadata.Old != nil && metadata.Old.Parent != "" {
+				plan.Parents[metadata.URN] = metadata.Old.Parent
+			}
+			if status, ok := planTags(metadata, requiredTags); ok {
+				plan.Untagged = append(plan.Untagged, status)
+			}
+			if metadata.Op == apitype.OpSame {
+				continue
+			}
+			plan.Changes = append(plan.Changes, PlannedChange{
+				URN:  metadata.URN,
+				Type: metadata.Type,
+				Op:   metadata.Op,
+			})
+		}
+	}()
+	_, err := stack.Preview(ctx, append(opts, optpreview.EventStreams(stream))...)
+	<-done
+	if err != nil {
+		if len(errs) > 0 {
+			return nil, util.NewReadableError(err, "Preview failed\n"+strings.Join(errs, "\n"))
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkPlan previews a deploy when it has to be checked against the protected
+// resources and required tags, or approved before it runs. It returns the path to the saved plan
+// that the deploy has to follow, so nothing that changes after the preview can
+// get around the checks.
+func (p *Project) checkPlan(ctx context.Context, input *StackInput, stack auto.Stack, completed *CompleteEvent) (planPath string, err error) {
+	protected := p.Protected() || len(p.app.Protect.Resources) > 0
+	if input.Command == "remove" && protected {
+		changes, parents := removeChanges(completed.Resources, input.Target)
+		return "", p.checkProtection(input, changes, parents)
+	}
+	// sst dev deploys on every change so the tags are only checked by the
+	// deploy itself instead of a preview
+	checkTags := len(p.app.RequiredTags) > 0 && !input.Dev
+	if input.Command != "deploy" || (!protected && input.ConfirmPlan == nil && !checkTags) {
+		return "", nil
+	}
+	opts := []optpreview.Option{
+		optpreview.Target(input.Target),
+		optpreview.TargetDependents(),
+	}
+	if protected || input.ConfirmPlan != nil {
+		path := filepath.Join(p.PathWorkingDir(), "plan.json")
+		opts = append(opts, optpreview.Plan(path))
+		defer func() {
+			if err != nil {
+				os.Remove(path)
+			}
+		}()
+		planPath = path
+	}
+	plan, err := previewPlan(ctx, stack, p.app.RequiredTags, opts...)
+	if err != nil {
+		return "", err
+	}
+	if checkTags {
+		err = p.checkRequiredTags(plan.Untagged)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = p.checkProtection(input, plan.Changes, plan.Parents)
+	if err != nil {
+		return "", err
+	}
+	if input.ConfirmPlan != nil {
+		ok, err := input.ConfirmPlan(plan)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrPlanRejected
+		}
+	}
+	return planPath, nil
+}
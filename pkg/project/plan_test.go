@@ -0,0 +1,89 @@
+package project
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// TestPlan previews a deploy against a local backend and deploys the saved
+// plan, the way --confirm and protected stages do.
+func TestPlan(t *testing.T) {
+	if _, err := exec.LookPath("pulumi"); err != nil {
+		t.Skip("pulumi is not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	components := []string{"Api", "Web"}
+	program := func(ctx *pulumi.Context) error {
+		for _, name := range components {
+			component := &pulumi.ResourceState{}
+			if err := ctx.RegisterComponentResource("sst:test:Component", name, component); err != nil {
+				return err
+			}
+			if err := ctx.RegisterResourceOutputs(component, pulumi.Map{"name": pulumi.String(name)}); err != nil {
+				return err
+			}
+		}
+		ctx.Export("count", pulumi.Int(len(components)))
+		return nil
+	}
+	stack, err := auto.UpsertStackInlineSource(ctx, "dev", "app", program,
+		auto.WorkDir(dir),
+		auto.PulumiHome(filepath.Join(dir, "home")),
+		auto.Project(workspace.Project{
+			Name:    "app",
+			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+			Backend: &workspace.ProjectBackend{URL: "file://" + dir},
+		}),
+		auto.EnvVars(map[string]string{
+			"PULUMI_CONFIG_PASSPHRASE": "test",
+			"PULUMI_SKIP_UPDATE_CHECK": "true",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planPath := filepath.Join(dir, "plan.json")
+	plan, err := previewPlan(ctx, stack, nil, optpreview.Plan(planPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	creates := 0
+	for _, change := range plan.Changes {
+		if change.Op == apitype.OpCreate {
+			creates++
+		}
+	}
+	if creates != 3 {
+		t.Fatalf("expected the stack and two components to be created, got %v", plan.Changes)
+	}
+	if _, err := os.Stat(planPath); err != nil {
+		t.Fatalf("expected the plan to be saved, got %v", err)
+	}
+	if _, err := stack.Up(ctx, optup.Plan(planPath)); err != nil {
+		t.Fatalf("expected the deploy to follow the plan, got %v", err)
+	}
+
+	// a change made after the preview is not part of the plan
+	_, err = previewPlan(ctx, stack, nil, optpreview.Plan(planPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	components = append(components, "Worker")
+	_, err = stack.Up(ctx, optup.Plan(planPath))
+	if err == nil || !strings.Contains(err.Error(), "plan") {
+		t.Fatalf("expected the deploy to be rejected by the plan, got %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package project
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+type ResourceTiming struct {
+	URN    string         `json:"urn"`
+	Type   string         `json:"type"`
+	Op     apitype.OpType `json:"op"`
+	Start  time.Time      `json:"start"`
+	End    time.Time      `json:"end"`
+	Failed bool           `json:"failed,omitempty"`
+}
+
+func (t ResourceTiming) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+type timingRecorder struct {
+	pending   map[string]*ResourceTiming
+	completed []ResourceTiming
+}
+
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{
+		pending:   map[string]*ResourceTiming{},
+		completed: []ResourceTiming{},
+	}
+}
+
+func (r *timingRecorder) start(metadata apitype.StepEventMetadata) {
+	if metadata.Op == apitype.OpSame {
+		return
+	}
+	r.pending[metadata.URN+string(metadata.Op)] = &ResourceTiming{
+		URN:   metadata.URN,
+		Type:  metadata.Type,
+		Op:    metadata.Op,
+		Start: time.Now(),
+	}
+}
+
+func (r *timingRecorder) end(metadata apitype.StepEventMetadata, failed bool) {
+	key := metadata.URN + string(metadata.Op)
+	match, ok := r.pending[key]
+	if !ok {
+		return
+	}
+	delete(r.pending, key)
+	match.End = time.Now()
+	match.Failed = failed
+	r.completed = append(r.completed, *match)
+}
+
+func (r *timingRecorder) result() []ResourceTiming {
+	result := append([]ResourceTiming{}, r.completed...)
+	now := time.Now()
+	for _, match := range r.pending {
+		item := *match
+		item.End = now
+		item.Failed = true
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// CriticalPath walks back from the resource that finished last, following the
+// dependency that finished most recently before each resource started.
+func CriticalPath(timings []ResourceTiming, resources []apitype.ResourceV3) []ResourceTiming {
+	if len(timings) == 0 {
+		return []ResourceTiming{}
+	}
+	byURN := map[string]ResourceTiming{}
+	last := timings[0]
+	for _, timing := range timings {
+		byURN[timing.URN] = timing
+		if timing.End.After(last.End) {
+			last = timing
+		}
+	}
+	dependencies := map[string][]string{}
+	for _, resource := range resources {
+		for _, dep := range resource.Dependencies {
+			dependencies[string(resource.URN)] = append(dependencies[string(resource.URN)], string(dep))
+		}
+	}
+
+	path := []ResourceTiming{last}
+	visited := map[string]bool{last.URN: true}
+	current := last
+	for {
+		var next *ResourceTiming
+		for _, dep := range dependencies[current.URN] {
+			match, ok := byURN[dep]
+			if !ok || visited[dep] || match.End.After(current.Start.Add(time.Second)) {
+				continue
+			}
+			if next == nil || match.End.After(next.End) {
+				next = &match
+			}
+		}
+		if next == nil {
+			break
+		}
+		visited[next.URN] = true
+		path = append([]ResourceTiming{*next}, path...)
+		current = *next
+	}
+	return path
+}
+
+// ChromeTrace formats the timings in the Trace Event Format that can be opened
+// in chrome://tracing or https://ui.perfetto.dev
+func ChromeTrace(timings []ResourceTiming) ([]byte, error) {
+	type traceEvent struct {
+		Name string                 `json:"name"`
+		Cat  string                 `json:"cat"`
+		Ph   string                 `json:"ph"`
+		Ts   int64                  `json:"ts"`
+		Dur  int64                  `json:"dur"`
+		Pid  int                    `json:"pid"`
+		Tid  int                    `json:"tid"`
+		Args map[string]interface{} `json:"args"`
+	}
+	events := []traceEvent{}
+	// spans on the same thread have to nest so overlapping resources get their own lane
+	lanes := []time.Time{}
+	for _, timing := range timings {
+		lane := -1
+		for index, end := range lanes {
+			if !end.After(timing.Start) {
+				lane = index
+				break
+			}
+		}
+		if lane == -1 {
+			lanes = append(lanes, time.Time{})
+			lane = len(lanes) - 1
+		}
+		lanes[lane] = timing.End
+		events = append(events, traceEvent{
+			Name: formatTimingName(timing.URN),
+			Cat:  string(timing.Op),
+			Ph:   "X",
+			Ts:   timing.Start.UnixMicro(),
+			Dur:  timing.Duration().Microseconds(),
+			Pid:  1,
+			Tid:  lane + 1,
+			Args: map[string]interface{}{
+				"urn":    timing.URN,
+				"type":   timing.Type,
+				"failed": timing.Failed,
+			},
+		})
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	}, "", "  ")
+}
+
+// OTLPTrace formats the timings as an OTLP/JSON trace with a root span for the
+// command and a child span for every resource.
+func OTLPTrace(app, stage, command string, timings []ResourceTiming) ([]byte, error) {
+	type attribute struct {
+		Key   string            `json:"key"`
+		Value map[string]string `json:"value"`
+	}
+	type span struct {
+		TraceID           string      `json:"traceId"`
+		SpanID            string      `json:"spanId"`
+		ParentSpanID      string      `json:"parentSpanId,omitempty"`
+		Name              string      `json:"name"`
+		Kind              int         `json:"kind"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+		Attributes        []attribute `json:"attributes"`
+		Status            struct {
+			Code int `json:"code"`
+		} `json:"status"`
+	}
+	str := func(key, value string) attribute {
+		return attribute{Key: key, Value: map[string]string{"stringValue": value}}
+	}
+	traceID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	rootID, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	root := span{
+		TraceID:    traceID,
+		SpanID:     rootID,
+		Name:       "sst " + command,
+		Kind:       1,
+		Attributes: []attribute{str("sst.app", app), str("sst.stage", stage)},
+	}
+	spans := []span{}
+	var start, end time.Time
+	for _, timing := range timings {
+		if start.IsZero() || timing.Start.Before(start) {
+			start = timing.Start
+		}
+		if timing.End.After(end) {
+			end = timing.End
+		}
+		spanID, err := randomHex(8)
+		if err != nil {
+			return nil, err
+		}
+		item := span{
+			TraceID:           traceID,
+			SpanID:            spanID,
+			ParentSpanID:      rootID,
+			Name:              formatTimingName(timing.URN),
+			Kind:              1,
+			StartTimeUnixNano: fmt.Sprint(timing.Start.UnixNano()),
+			EndTimeUnixNano:   fmt.Sprint(timing.End.UnixNano()),
+			Attributes: []attribute{
+				str("sst.urn", timing.URN),
+				str("sst.type", timing.Type),
+				str("sst.op", string(timing.Op)),
+			},
+		}
+		if timing.Failed {
+			item.Status.Code = 2
+			root.Status.Code = 2
+		}
+		spans = append(spans, item)
+	}
+	root.StartTimeUnixNano = fmt.Sprint(start.UnixNano())
+	root.EndTimeUnixNano = fmt.Sprint(end.UnixNano())
+	spans = append([]span{root}, spans...)
+
+	return json.MarshalIndent(map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []attribute{str("service.name", "sst")},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "sst"},
+						"spans": spans,
+					},
+				},
+			},
+		},
+	}, "", "  ")
+}
+
+func formatTimingName(urn string) string {
+	return resource.URN(urn).Name()
+}
+
+func randomHex(length int) (string, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
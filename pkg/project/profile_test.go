@@ -0,0 +1,216 @@
+package project
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestTimingRecorder(t *testing.T) {
+	recorder := newTimingRecorder()
+	vpc := apitype.StepEventMetadata{URN: "urn:pulumi:dev::app::aws:ec2/vpc:Vpc::Vpc", Op: apitype.OpCreate}
+	bucket := apitype.StepEventMetadata{URN: "urn:pulumi:dev::app::aws:s3/bucketV2:BucketV2::Bucket", Op: apitype.OpCreate}
+	same := apitype.StepEventMetadata{URN: "urn:pulumi:dev::app::aws:sqs/queue:Queue::Queue", Op: apitype.OpSame}
+	recorder.start(vpc)
+	recorder.start(bucket)
+	recorder.start(same)
+	recorder.end(vpc, false)
+	recorder.end(same, false)
+
+	result := recorder.result()
+	if len(result) != 2 {
+		t.Fatalf("expected unchanged resources to be skipped, got %v", result)
+	}
+	for _, timing := range result {
+		failed := timing.URN == bucket.URN
+		if timing.Failed != failed {
+			t.Errorf("expected %v failed to be %v", timing.URN, failed)
+		}
+		if timing.End.Before(timing.Start) {
+			t.Errorf("expected %v to end after it started", timing.URN)
+		}
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	urn := func(name string) string {
+		return "urn:pulumi:dev::app::aws:test:Resource::" + name
+	}
+	timing := func(name string, start, end int) ResourceTiming {
+		return ResourceTiming{
+			URN:   urn(name),
+			Op:    apitype.OpCreate,
+			Start: base.Add(time.Duration(start) * time.Second),
+			End:   base.Add(time.Duration(end) * time.Second),
+		}
+	}
+	// Vpc -> Subnet -> Cluster -> Service, with the Role only holding up the
+	// Service for a bit and the Bucket not depending on anything
+	timings := []ResourceTiming{
+		timing("Vpc", 0, 10),
+		timing("Role", 0, 5),
+		timing("Subnet", 10, 15),
+		timing("Bucket", 0, 20),
+		timing("Cluster", 15, 60),
+		timing("Service", 60, 90),
+	}
+	resources := []apitype.ResourceV3{
+		{URN: resource.URN(urn("Subnet")), Dependencies: []resource.URN{resource.URN(urn("Vpc"))}},
+		{URN: resource.URN(urn("Cluster")), Dependencies: []resource.URN{resource.URN(urn("Subnet"))}},
+		{URN: resource.URN(urn("Service")), Dependencies: []resource.URN{resource.URN(urn("Role")), resource.URN(urn("Cluster"))}},
+	}
+
+	tests := []struct {
+		name      string
+		timings   []ResourceTiming
+		resources []apitype.ResourceV3
+		expected  []string
+	}{
+		{"empty", nil, nil, []string{}},
+		{"no dependencies", timings, nil, []string{"Service"}},
+		{"chain", timings, resources, []string{"Vpc", "Subnet", "Cluster", "Service"}},
+		{"last resource is independent", append(timings, timing("Dns", 0, 100)), resources, []string{"Dns"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			names := []string{}
+			for _, item := range CriticalPath(test.timings, test.resources) {
+				names = append(names, formatTimingName(item.URN))
+			}
+			if !slices.Equal(names, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, names)
+			}
+		})
+	}
+}
+
+func TestChromeTrace(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	timings := []ResourceTiming{
+		{URN: "urn:pulumi:dev::app::aws:ec2/vpc:Vpc::Vpc", Type: "aws:ec2/vpc:Vpc", Op: apitype.OpCreate, Start: base, End: base.Add(2 * time.Second)},
+		{URN: "urn:pulumi:dev::app::aws:iam/role:Role::Role", Type: "aws:iam/role:Role", Op: apitype.OpCreate, Start: base.Add(time.Second), End: base.Add(3 * time.Second)},
+		{URN: "urn:pulumi:dev::app::aws:ec2/subnet:Subnet::Subnet", Type: "aws:ec2/subnet:Subnet", Op: apitype.OpUpdate, Start: base.Add(2 * time.Second), End: base.Add(4 * time.Second), Failed: true},
+	}
+	data, err := ChromeTrace(timings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name string                 `json:"name"`
+			Cat  string                 `json:"cat"`
+			Ph   string                 `json:"ph"`
+			Ts   int64                  `json:"ts"`
+			Dur  int64                  `json:"dur"`
+			Tid  int                    `json:"tid"`
+			Args map[string]interface{} `json:"args"`
+		} `json:"traceEvents"`
+		DisplayTimeUnit string `json:"displayTimeUnit"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.TraceEvents) != 3 || trace.DisplayTimeUnit != "ms" {
+		t.Fatalf("unexpected trace %s", data)
+	}
+	tests := []struct {
+		name string
+		cat  string
+		dur  int64
+		tid  int
+	}{
+		{"Vpc", "create", 2_000_000, 1},
+		// overlaps the Vpc so it gets its own lane
+		{"Role", "create", 2_000_000, 2},
+		// starts when the Vpc ends so it can reuse its lane
+		{"Subnet", "update", 2_000_000, 1},
+	}
+	for i, test := range tests {
+		event := trace.TraceEvents[i]
+		if event.Name != test.name || event.Cat != test.cat || event.Ph != "X" || event.Dur != test.dur || event.Tid != test.tid {
+			t.Errorf("unexpected event %+v", event)
+		}
+		if event.Ts != timings[i].Start.UnixMicro() || event.Args["urn"] != timings[i].URN {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+	if trace.TraceEvents[2].Args["failed"] != true {
+		t.Error("expected the failure to be recorded")
+	}
+}
+
+func TestOTLPTrace(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	timings := []ResourceTiming{
+		{URN: "urn:pulumi:dev::app::aws:ec2/vpc:Vpc::Vpc", Type: "aws:ec2/vpc:Vpc", Op: apitype.OpCreate, Start: base, End: base.Add(2 * time.Second)},
+		{URN: "urn:pulumi:dev::app::aws:ec2/subnet:Subnet::Subnet", Type: "aws:ec2/subnet:Subnet", Op: apitype.OpCreate, Start: base.Add(time.Second), End: base.Add(3 * time.Second), Failed: true},
+	}
+	data, err := OTLPTrace("app", "dev", "deploy", timings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type attribute struct {
+		Key   string            `json:"key"`
+		Value map[string]string `json:"value"`
+	}
+	var trace struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []attribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID           string      `json:"traceId"`
+					SpanID            string      `json:"spanId"`
+					ParentSpanID      string      `json:"parentSpanId"`
+					Name              string      `json:"name"`
+					StartTimeUnixNano string      `json:"startTimeUnixNano"`
+					EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+					Attributes        []attribute `json:"attributes"`
+					Status            struct {
+						Code int `json:"code"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.ResourceSpans) != 1 || len(trace.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected trace %s", data)
+	}
+	if attr := trace.ResourceSpans[0].Resource.Attributes; len(attr) != 1 || attr[0].Value["stringValue"] != "sst" {
+		t.Fatalf("unexpected resource %v", attr)
+	}
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("expected a root span and one for every resource, got %d", len(spans))
+	}
+	root := spans[0]
+	if root.Name != "sst deploy" || root.ParentSpanID != "" || len(root.TraceID) != 32 || len(root.SpanID) != 16 {
+		t.Fatalf("unexpected root span %+v", root)
+	}
+	if root.StartTimeUnixNano != "1717243200000000000" || root.EndTimeUnixNano != "1717243203000000000" {
+		t.Fatalf("expected the root span to cover every resource, got %+v", root)
+	}
+	if root.Status.Code != 2 {
+		t.Fatal("expected the root span to fail when a resource fails")
+	}
+	for i, span := range spans[1:] {
+		if span.TraceID != root.TraceID || span.ParentSpanID != root.SpanID || span.SpanID == root.SpanID {
+			t.Errorf("expected %v to be a child of the root span", span.Name)
+		}
+		if span.Name != formatTimingName(timings[i].URN) || span.Attributes[0].Value["stringValue"] != timings[i].URN {
+			t.Errorf("unexpected span %+v", span)
+		}
+	}
+	if spans[1].Status.Code != 0 || spans[2].Status.Code != 2 {
+		t.Error("expected only the failed resource to have an error status")
+	}
+}
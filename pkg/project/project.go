@@ -25,12 +25,16 @@ import (
 )
 
 type App struct {
-	Name      string                 `json:"name"`
-	Stage     string                 `json:"stage"`
-	Removal   string                 `json:"removal"`
-	Providers map[string]interface{} `json:"providers"`
-	Home      string                 `json:"home"`
-	Version   string                 `json:"version"`
+	Name         string                 `json:"name"`
+	Stage        string                 `json:"stage"`
+	Removal      string                 `json:"removal"`
+	Providers    map[string]interface{} `json:"providers"`
+	Home         string                 `json:"home"`
+	Version      string                 `json:"version"`
+	Hooks        AppHooks               `json:"hooks"`
+	Webhooks     []AppWebhook           `json:"webhooks"`
+	Protect      AppProtect             `json:"protect"`
+	RequiredTags []string               `json:"requiredTags"`
 	// Deprecated: Backend is now Home
 	Backend string `json:"backend"`
 	// Deprecated: RemovalPolicy is now Removal
@@ -46,6 +50,7 @@ type Project struct {
 	home            provider.Home
 	env             map[string]string
 	loadedProviders map[string]provider.Provider
+	configContext   *js.Context
 	Runtime         *runtime.Collection
 }
 
@@ -231,6 +236,10 @@ console.log("~j" + JSON.stringify(mod.app({
 			if proj.app.Removal != "remove" && proj.app.Removal != "retain" && proj.app.Removal != "retain-all" {
 				return nil, fmt.Errorf("Removal must be one of: remove, retain, retain-all")
 			}
+
+			if err := validateWebhooks(proj.app.Webhooks); err != nil {
+				return nil, err
+			}
 			continue
 		}
 	}
@@ -340,6 +349,9 @@ func (p *Project) Provider(name string) (provider.Provider, bool) {
 }
 
 func (p *Project) Cleanup() error {
+	if p.configContext != nil {
+		p.configContext.Dispose()
+	}
 	if flag.SST_NO_CLEANUP {
 		return nil
 	}
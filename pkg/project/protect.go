@@ -0,0 +1,155 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/ion/internal/util"
+)
+
+// AppProtect marks stages as protected, either for every stage or the ones
+// that match one of the patterns. Resources that match one of the patterns in
+// Resources can never be deleted or replaced, in any stage.
+type AppProtect struct {
+	Enabled   bool     `json:"enabled,omitempty"`
+	Stages    []string `json:"stages,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// protect can also be set to a boolean
+func (a *AppProtect) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		*a = AppProtect{Enabled: enabled}
+		return nil
+	}
+	type alias AppProtect
+	return json.Unmarshal(data, (*alias)(a))
+}
+
+// Protected returns whether the current stage requires confirmation before
+// resources are deleted or replaced.
+func (p *Project) Protected() bool {
+	if p.app.Protect.Enabled {
+		return true
+	}
+	for _, pattern := range p.app.Protect.Stages {
+		if matchGlob(pattern, p.app.Stage) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlob(pattern string, input string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	match, _ := regexp.MatchString(expr, input)
+	return match
+}
+
+// checkProtection fails if one of the changes deletes or replaces a resource
+// that can never be destroyed, or one of its children. Parents maps every
+// resource to its parent. On a protected stage any other delete or replace has
+// to be confirmed or explicitly allowed.
+func (p *Project) checkProtection(input *StackInput, changes []PlannedChange, parents map[string]string) error {
+	destructive := []PlannedChange{}
+	for _, change := range changes {
+		if !change.Destructive() {
+			continue
+		}
+		if protected := p.protectedResource(change.URN, parents); protected != "" {
+			name := resource.URN(change.URN).Name()
+			if protected != change.URN {
+				name = fmt.Sprintf("%v in %v", name, resource.URN(protected).Name())
+			}
+			return util.NewReadableError(nil, fmt.Sprintf("%v is protected and cannot be %v. Remove it from the protected resources in your sst.config.ts first.", name, opVerb(change.Op)))
+		}
+		destructive = append(destructive, change)
+	}
+	if len(destructive) == 0 || !p.Protected() || input.AllowDestroy {
+		return nil
+	}
+	if input.ConfirmDestroy == nil {
+		return util.NewReadableError(nil, fmt.Sprintf("The %v stage is protected and %v resources would be deleted or replaced. Pass in --allow-destroy to continue.", p.app.Stage, len(destructive)))
+	}
+	ok, err := input.ConfirmDestroy(destructive)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return util.NewReadableError(nil, fmt.Sprintf("Cancelled. The %v stage is protected.", p.app.Stage))
+	}
+	return nil
+}
+
+// protectedResource returns the resource, or the first of its parents, that
+// matches one of the protected patterns.
+func (p *Project) protectedResource(urn string, parents map[string]string) string {
+	visited := map[string]bool{}
+	for current := urn; current != "" && !visited[current]; current = parents[current] {
+		visited[current] = true
+		for _, pattern := range p.app.Protect.Resources {
+			if matchGlob(pattern, current) {
+				return current
+			}
+		}
+	}
+	return ""
+}
+
+func opVerb(op apitype.OpType) string {
+	if op == apitype.OpDelete || op == apitype.OpDeleteReplaced {
+		return "deleted"
+	}
+	return "replaced"
+}
+
+// removeChanges lists the resources a remove is going to delete, along with
+// the parent of every resource. When there are targets, the resources that
+// depend on them or are their children are deleted too.
+func removeChanges(resources []apitype.ResourceV3, target []string) ([]PlannedChange, map[string]string) {
+	parents := map[string]string{}
+	for _, item := range resources {
+		if item.Parent != "" {
+			parents[string(item.URN)] = string(item.Parent)
+		}
+	}
+	removed := map[string]bool{}
+	for _, urn := range target {
+		removed[urn] = true
+	}
+	// keep going until no more dependents are found
+	for changed := len(target) > 0; changed; {
+		changed = false
+		for _, item := range resources {
+			urn := string(item.URN)
+			if removed[urn] {
+				continue
+			}
+			if removed[string(item.Parent)] || slices.ContainsFunc(item.Dependencies, func(dep resource.URN) bool { return removed[string(dep)] }) {
+				removed[urn] = true
+				changed = true
+			}
+		}
+	}
+	changes := []PlannedChange{}
+	for _, item := range resources {
+		if strings.HasPrefix(string(item.Type), "pulumi:") {
+			continue
+		}
+		if len(target) > 0 && !removed[string(item.URN)] {
+			continue
+		}
+		changes = append(changes, PlannedChange{
+			URN:  string(item.URN),
+			Type: string(item.Type),
+			Op:   apitype.OpDelete,
+		})
+	}
+	return changes, parents
+}
@@ -0,0 +1,86 @@
+package project
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestCheckProtection(t *testing.T) {
+	var app App
+	err := json.Unmarshal([]byte(`{"stage":"staging-1","protect":{"stages":["production","staging-*"],"resources":["*::sst:aws:Postgres::Database*"]}}`), &app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Project{app: &app}
+	if !p.Protected() {
+		t.Fatal("expected stage to be protected")
+	}
+
+	bucket := PlannedChange{URN: "urn:pulumi:staging-1::app::sst:aws:Bucket::Assets", Op: apitype.OpReplace}
+	database := PlannedChange{URN: "urn:pulumi:staging-1::app::sst:aws:Postgres::Database", Op: apitype.OpDelete}
+	update := PlannedChange{URN: bucket.URN, Op: apitype.OpUpdate}
+
+	if err := p.checkProtection(&StackInput{}, []PlannedChange{update}, nil); err != nil {
+		t.Fatalf("expected updates to be allowed, got %v", err)
+	}
+	if err := p.checkProtection(&StackInput{}, []PlannedChange{bucket}, nil); err == nil {
+		t.Fatal("expected replace without confirmation to fail")
+	}
+	confirmed := []PlannedChange{}
+	err = p.checkProtection(&StackInput{ConfirmDestroy: func(changes []PlannedChange) (bool, error) {
+		confirmed = changes
+		return true, nil
+	}}, []PlannedChange{update, bucket}, nil)
+	if err != nil || len(confirmed) != 1 {
+		t.Fatalf("expected only the replace to be confirmed, got %v %v", confirmed, err)
+	}
+	if err := p.checkProtection(&StackInput{AllowDestroy: true}, []PlannedChange{database}, nil); err == nil {
+		t.Fatal("expected protected resource to never be deleted")
+	}
+	cluster := PlannedChange{URN: "urn:pulumi:staging-1::app::sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster", Op: apitype.OpReplace}
+	parents := map[string]string{cluster.URN: database.URN, database.URN: "urn:pulumi:staging-1::app::pulumi:pulumi:Stack::app-staging-1"}
+	if err := p.checkProtection(&StackInput{AllowDestroy: true}, []PlannedChange{cluster}, parents); err == nil {
+		t.Fatal("expected children of a protected resource to never be replaced")
+	}
+
+	err = json.Unmarshal([]byte(`{"stage":"dev","protect":true}`), &app)
+	if err != nil || !app.Protect.Enabled {
+		t.Fatalf("expected protect to accept a boolean, got %v", err)
+	}
+}
+
+func TestRemoveChanges(t *testing.T) {
+	urn := func(name string) resource.URN {
+		return resource.URN("urn:pulumi:dev::app::" + name)
+	}
+	resources := []apitype.ResourceV3{
+		{URN: urn("pulumi:pulumi:Stack::app-dev"), Type: "pulumi:pulumi:Stack"},
+		{URN: urn("sst:aws:Vpc::Vpc"), Type: "sst:aws:Vpc", Parent: urn("pulumi:pulumi:Stack::app-dev")},
+		{URN: urn("sst:aws:Vpc$aws:ec2/vpc:Vpc::VpcVpc"), Type: "aws:ec2/vpc:Vpc", Parent: urn("sst:aws:Vpc::Vpc")},
+		{URN: urn("sst:aws:Postgres::Database"), Type: "sst:aws:Postgres", Dependencies: []resource.URN{urn("sst:aws:Vpc$aws:ec2/vpc:Vpc::VpcVpc")}},
+		{URN: urn("sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster"), Type: "aws:rds/cluster:Cluster", Parent: urn("sst:aws:Postgres::Database")},
+		{URN: urn("sst:aws:Bucket::Assets"), Type: "sst:aws:Bucket"},
+	}
+
+	changes, parents := removeChanges(resources, []string{string(urn("sst:aws:Vpc::Vpc"))})
+	removed := []string{}
+	for _, change := range changes {
+		removed = append(removed, resource.URN(change.URN).Name())
+	}
+	expected := []string{"Vpc", "VpcVpc", "Database", "DatabaseCluster"}
+	if !slices.Equal(removed, expected) {
+		t.Fatalf("expected %v to be removed, got %v", expected, removed)
+	}
+	if parents[string(urn("sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster"))] != string(urn("sst:aws:Postgres::Database")) {
+		t.Fatalf("unexpected parents %v", parents)
+	}
+
+	changes, _ = removeChanges(resources, nil)
+	if len(changes) != 5 {
+		t.Fatalf("expected every resource but the stack to be removed, got %v", changes)
+	}
+}
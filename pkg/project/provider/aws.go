@@ -190,6 +190,28 @@ func (a *AwsHome) removeData(key, app, stage string) error {
 	return nil
 }
 
+func (a *AwsHome) listData(key, app, stage string) ([]string, error) {
+	s3Client := s3.NewFromConfig(a.provider.config)
+
+	prefix := path.Join(key, app, stage) + "/"
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(a.bootstrap.State),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	result := []string{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			result = append(result, trimDataName(strings.TrimPrefix(*object.Key, prefix)))
+		}
+	}
+	return result, nil
+}
+
 func (a *AwsHome) getPassphrase(app string, stage string) (string, error) {
 	ssmClient := ssm.NewFromConfig(a.provider.config)
 
@@ -221,6 +243,22 @@ func (a *AwsHome) setPassphrase(app, stage, passphrase string) error {
 	return err
 }
 
+func (a *AwsHome) removePassphrase(app, stage string) error {
+	ssmClient := ssm.NewFromConfig(a.provider.config)
+
+	_, err := ssmClient.DeleteParameter(context.TODO(), &ssm.DeleteParameterInput{
+		Name: aws.String(a.pathForPassphrase(app, stage)),
+	})
+	if err != nil {
+		pnf := &ssmTypes.ParameterNotFound{}
+		if errors.As(err, &pnf) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
 func (a *AwsHome) Bootstrap() error {
 	data, err := AwsBootstrap(a.provider.config)
 	if err != nil {
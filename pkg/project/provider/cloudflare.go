@@ -3,12 +3,16 @@ package provider
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	_ "unsafe"
 
 	cloudflare "github.com/cloudflare/cloudflare-go"
@@ -155,6 +159,45 @@ func (c *CloudflareHome) removeData(kind, app, stage string) error {
 	return nil
 }
 
+func (c *CloudflareHome) listData(kind, app, stage string) ([]string, error) {
+	prefix := path.Join(kind, app, stage) + "/"
+	result := []string{}
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("delimiter", "/")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		data, err := makeRequestContext(c.provider.api, context.Background(), http.MethodGet, "/accounts/"+c.provider.identifier.Identifier+"/r2/buckets/"+c.bootstrap.State+"/objects?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response struct {
+			Result []struct {
+				Key string `json:"key"`
+			} `json:"result"`
+			ResultInfo struct {
+				Cursor      string `json:"cursor"`
+				IsTruncated bool   `json:"is_truncated"`
+			} `json:"result_info"`
+		}
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range response.Result {
+			result = append(result, trimDataName(strings.TrimPrefix(object.Key, prefix)))
+		}
+		if !response.ResultInfo.IsTruncated || response.ResultInfo.Cursor == "" {
+			break
+		}
+		cursor = response.ResultInfo.Cursor
+	}
+	return result, nil
+}
+
 // these should go into secrets manager once it's out of beta
 func (c *CloudflareHome) setPassphrase(app, stage string, passphrase string) error {
 	return c.putData("passphrase", app, stage, bytes.NewReader([]byte(passphrase)))
@@ -174,3 +217,7 @@ func (c *CloudflareHome) getPassphrase(app, stage string) (string, error) {
 	}
 	return string(read), nil
 }
+
+func (c *CloudflareHome) removePassphrase(app, stage string) error {
+	return c.removeData("passphrase", app, stage)
+}
@@ -55,7 +55,30 @@ func (l *LocalHome) putData(key, app, stage string, data io.Reader) error {
 
 func (l *LocalHome) removeData(key, app, stage string) error {
 	p := l.pathForData(key, app, stage)
-	return os.Remove(p)
+	err := os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalHome) listData(key, app, stage string) ([]string, error) {
+	dir := filepath.Join(global.ConfigDir(), "state", key, app, stage)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	result := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		result = append(result, trimDataName(entry.Name()))
+	}
+	return result, nil
 }
 
 // these should go into secrets manager once it's out of beta
@@ -81,3 +104,7 @@ func (c *LocalHome) getPassphrase(app, stage string) (string, error) {
 func (l *LocalHome) pathForData(key, app, stage string) string {
 	return filepath.Join(global.ConfigDir(), "state", key, app, fmt.Sprintf("%v.json", stage))
 }
+
+func (c *LocalHome) removePassphrase(app, stage string) error {
+	return c.removeData("passphrase", app, stage)
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/sst/ion/pkg/flag"
@@ -22,8 +24,10 @@ type Home interface {
 	getData(key, app, stage string) (io.Reader, error)
 	putData(key, app, stage string, data io.Reader) error
 	removeData(key, app, stage string) error
+	listData(key, app, stage string) ([]string, error)
 	setPassphrase(app, stage string, passphrase string) error
 	getPassphrase(app, stage string) (string, error)
+	removePassphrase(app, stage string) error
 }
 
 type DevTransport struct {
@@ -156,6 +160,83 @@ func PutUpdate(backend Home, app, stage string, update Update) error {
 	return putData(backend, "update", app, stage+"/"+update.ID, false, update)
 }
 
+func ListStages(backend Home, app string) ([]string, error) {
+	slog.Info("listing stages", "app", app)
+	return backend.listData("app", app, "")
+}
+
+// update ids are descending so the most recent update sorts first
+func GetLastUpdate(backend Home, app, stage string) (*Update, error) {
+	ids, err := backend.listData("update", app, stage)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+	var update Update
+	err = getData(backend, "update", app, stage+"/"+ids[0], false, &update)
+	if err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+func GetResourceCount(backend Home, app, stage string) (int, error) {
+	var state struct {
+		Checkpoint struct {
+			Latest struct {
+				Resources []json.RawMessage `json:"resources"`
+			} `json:"latest"`
+		} `json:"checkpoint"`
+	}
+	err := getData(backend, "app", app, stage, false, &state)
+	if err != nil {
+		return 0, err
+	}
+	return len(state.Checkpoint.Latest.Resources), nil
+}
+
+type Metadata struct {
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+func (m Metadata) Expired() bool {
+	return m.Expires != nil && m.Expires.Before(time.Now())
+}
+
+func GetMetadata(backend Home, app, stage string) (Metadata, error) {
+	var metadata Metadata
+	err := getData(backend, "metadata", app, stage, false, &metadata)
+	return metadata, err
+}
+
+func PutMetadata(backend Home, app, stage string, metadata Metadata) error {
+	slog.Info("putting metadata", "app", app, "stage", stage)
+	return putData(backend, "metadata", app, stage, false, metadata)
+}
+
+// Purge removes everything the home stores for a stage. It should only be
+// called once the resources in the stage have been removed.
+func Purge(backend Home, app, stage string) error {
+	slog.Info("purging stage", "app", app, "stage", stage)
+	for _, key := range []string{"secret", "metadata", "lock", "app"} {
+		err := backend.removeData(key, app, stage)
+		if err != nil {
+			return err
+		}
+	}
+	err := backend.removePassphrase(app, stage)
+	if err != nil {
+		return err
+	}
+	if cache, ok := passphraseCache[backend]; ok {
+		delete(cache, app+stage)
+	}
+	return nil
+}
+
 func GetSecrets(backend Home, app, stage string) (map[string]string, error) {
 	if stage == "" {
 		stage = "_fallback"
@@ -268,6 +349,15 @@ func Lock(backend Home, updateID, version, command, app, stage string) error {
 	return nil
 }
 
+func IsLocked(backend Home, app, stage string) (bool, error) {
+	var lockData lockData
+	err := getData(backend, "lock", app, stage, false, &lockData)
+	if err != nil {
+		return false, err
+	}
+	return !lockData.Created.IsZero(), nil
+}
+
 func Unlock(backend Home, app, stage string) error {
 	slog.Info("unlocking", "app", app, "stage", stage)
 	return removeData(backend, "lock", app, stage)
@@ -352,3 +442,7 @@ func getData(backend Home, key, app, stage string, encrypted bool, out interface
 func removeData(backend Home, key, app, stage string) error {
 	return backend.removeData(key, app, stage)
 }
+
+func trimDataName(name string) string {
+	return strings.TrimSuffix(name, ".json")
+}
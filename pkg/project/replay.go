@@ -0,0 +1,121 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/ion/pkg/project/common"
+)
+
+// Replay reads an event log written by Run and passes the events to publish in
+// the same order and shape as they were published during the original run. The
+// delay between events is divided by speed, a speed of 0 replays instantly.
+func Replay(ctx context.Context, path string, speed float64, publish func(interface{})) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	recorded := []events.EngineEvent{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event events.EngineEvent
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return err
+		}
+		recorded = append(recorded, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	publish(replayCommand(recorded))
+	state := newRunState(CommonErrors)
+	for index, event := range recorded {
+		if index > 0 && speed > 0 {
+			delay := time.Duration(float64(event.Timestamp-recorded[index-1].Timestamp) * float64(time.Second) / speed)
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fields, ok := state.process(event)
+		if !ok {
+			continue
+		}
+		for _, field := range fields {
+			publish(field)
+		}
+	}
+
+	publish(&CompleteEvent{
+		Links:       common.Links{},
+		Versions:    map[string]int{},
+		ImportDiffs: state.importDiffs,
+		Devs:        Devs{},
+		Tunnels:     map[string]Tunnel{},
+		Hints:       map[string]string{},
+		Outputs:     map[string]interface{}{},
+		Errors:      state.errors,
+		Finished:    state.finished,
+		Resources:   []apitype.ResourceV3{},
+		Timings:     state.timings.result(),
+	})
+	return nil
+}
+
+// the event log does not record the command so it is inferred from the
+// resource operations
+func replayCommand(recorded []events.EngineEvent) *StackCommandEvent {
+	result := &StackCommandEvent{
+		Command: "deploy",
+		Version: "replay",
+	}
+	deletes := 0
+	changes := 0
+	for _, event := range recorded {
+		if event.ResourcePreEvent == nil {
+			continue
+		}
+		metadata := event.ResourcePreEvent.Metadata
+		if result.App == "" {
+			urn := resource.URN(metadata.URN)
+			if urn.IsValid() {
+				result.App = urn.Project().String()
+				result.Stage = urn.Stack().String()
+			}
+		}
+		if event.ResourcePreEvent.Planning {
+			result.Command = "diff"
+		}
+		if metadata.Op == apitype.OpSame || metadata.Op == apitype.OpRead || metadata.Op == apitype.OpRefresh {
+			continue
+		}
+		changes++
+		if metadata.Op == apitype.OpDelete {
+			deletes++
+		}
+	}
+	if result.Command != "diff" && changes > 0 && deletes == changes {
+		result.Command = "remove"
+	}
+	return result
+}
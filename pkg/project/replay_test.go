@@ -0,0 +1,53 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var recordedLog = strings.Join([]string{
+	`{"sequence":1,"timestamp":100,"resourcePreEvent":{"metadata":{"op":"create","urn":"urn:pulumi:dev::myapp::sst:aws:Bucket::Assets","type":"sst:aws:Bucket","provider":""}}}`,
+	`{"sequence":2,"timestamp":101,"diagnosticEvent":{"urn":"urn:pulumi:dev::myapp::sst:aws:Bucket::Assets","message":"BucketAlreadyExists","color":"never","severity":"error"}}`,
+	`{"sequence":3,"timestamp":101,"diagnosticEvent":{"message":"update failed","color":"never","severity":"error"}}`,
+	`{"sequence":4,"timestamp":102,"summaryEvent":{"maybeCorrupt":false,"durationSeconds":2,"resourceChanges":null,"PolicyPacks":null}}`,
+}, "\n")
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.log")
+	if err := os.WriteFile(path, []byte(recordedLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	published := []interface{}{}
+	err := Replay(context.Background(), path, 0, func(evt interface{}) {
+		published = append(published, evt)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command, ok := published[0].(*StackCommandEvent)
+	if !ok {
+		t.Fatalf("Expected first event to be a StackCommandEvent, got %T", published[0])
+	}
+	if command.App != "myapp" || command.Stage != "dev" || command.Command != "deploy" {
+		t.Errorf("Expected myapp/dev deploy, got %v/%v %v", command.App, command.Stage, command.Command)
+	}
+
+	complete, ok := published[len(published)-1].(*CompleteEvent)
+	if !ok {
+		t.Fatalf("Expected last event to be a CompleteEvent, got %T", published[len(published)-1])
+	}
+	if !complete.Finished {
+		t.Errorf("Expected replay to be finished")
+	}
+	if len(complete.Errors) != 1 || complete.Errors[0].Message != "BucketAlreadyExists" {
+		t.Errorf("Expected one BucketAlreadyExists error, got %v", complete.Errors)
+	}
+	// the command, 3 published events, and the complete event
+	if len(published) != 5 {
+		t.Errorf("Expected 5 published events, got %v", len(published))
+	}
+}
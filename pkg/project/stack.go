@@ -43,11 +43,18 @@ type BuildFailedEvent struct {
 }
 
 type StackInput struct {
-	Command    string
-	Target     []string
-	ServerPort int
-	Dev        bool
-	Verbose    bool
+	Command      string
+	Target       []string
+	ServerPort   int
+	Dev          bool
+	Verbose      bool
+	AllowDestroy bool
+	// ConfirmDestroy is called with the deletes and replacements on a
+	// protected stage. Without it they fail.
+	ConfirmDestroy func(changes []PlannedChange) (bool, error)
+	// ConfirmPlan is called with the result of a preview before a deploy,
+	// which then makes exactly the changes in it.
+	ConfirmPlan func(plan *Plan) (bool, error)
 }
 
 type ConcurrentUpdateEvent struct{}
@@ -87,6 +94,7 @@ type CompleteEvent struct {
 	Resources   []apitype.ResourceV3
 	ImportDiffs map[string][]ImportDiff
 	Tunnels     map[string]Tunnel
+	Timings     []ResourceTiming
 }
 
 type Tunnel struct {
@@ -112,51 +120,10 @@ type StackCommandEvent struct {
 }
 
 type Error struct {
-	Message string   `json:"message"`
-	URN     string   `json:"urn"`
-	Help    []string `json:"help"`
-}
-
-type CommonError struct {
-	Code    string   `json:"code"`
-	Message string   `json:"message"`
-	Short   []string `json:"short"`
-	Long    []string `json:"long"`
-}
-
-var CommonErrors = []CommonError{
-	{
-		Code:    "TooManyCacheBehaviors",
-		Message: "TooManyCacheBehaviors: Your request contains more CacheBehaviors than are allowed per distribution",
-		Short: []string{
-			"There are too many top-level files and directories inside your app's public asset directory. Move some of them inside subdirectories.",
-			"Learn more about this https://sst.dev/docs/common-errors#toomanycachebehaviors",
-		},
-		Long: []string{
-			"This error usually happens to `SvelteKit`, `SolidStart`, `Nuxt`, and `Analog` components.",
-			"",
-			"CloudFront distributions have a **limit of 25 cache behaviors** per distribution. Each top-level file or directory in your frontend app's asset directory creates a cache behavior.",
-			"",
-			"For example, in the case of SvelteKit, the static assets are in the `static/` directory. If you have a file and a directory in it, it'll create 2 cache behaviors.",
-			"",
-			"```bash frame=\"none\"",
-			"static/",
-			"├── icons/       # Cache behavior for /icons/*",
-			"└── logo.png     # Cache behavior for /logo.png",
-			"```",
-			"So if you have many of these at the top-level, you'll hit the limit. You can request a limit increase through the AWS Support.",
-			"",
-			"Alternatively, you can move some of these into subdirectories. For example, moving them to an `images/` directory, will only create 1 cache behavior.",
-			"",
-			"```bash frame=\"none\"",
-			"static/",
-			"└── images/      # Cache behavior for /images/*",
-			"    ├── icons/",
-			"    └── logo.png",
-			"```",
-			"Learn more about these [CloudFront limits](https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/cloudfront-limits.html#limits-web-distributions).",
-		},
-	},
+	Message  string   `json:"message"`
+	URN      string   `json:"urn"`
+	Help     []string `json:"help"`
+	Severity string   `json:"severity,omitempty"`
 }
 
 var ErrStackRunFailed = fmt.Errorf("stack run had errors")
@@ -175,6 +142,7 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	})
 
 	updateID := id.Descending()
+	started := time.Now()
 	if input.Command != "diff" {
 		err := p.Lock(updateID, input.Command)
 		if err != nil {
@@ -230,8 +198,6 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 		return err
 	}
 
-	outfile := filepath.Join(p.PathPlatformDir(), fmt.Sprintf("sst.config.%v.mjs", time.Now().UnixMilli()))
-
 	env := map[string]string{}
 	for key, value := range p.Env() {
 		env[key] = value
@@ -267,18 +233,18 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	if err != nil {
 		return err
 	}
+	settings := workspace.Project{
+		Name:    tokens.PackageName(p.app.Name),
+		Runtime: workspace.NewProjectRuntimeInfo("nodejs", nil),
+		Backend: &workspace.ProjectBackend{
+			URL: fmt.Sprintf("file://%v", p.PathWorkingDir()),
+		},
+	}
 	ws, err := auto.NewLocalWorkspace(ctx,
 		auto.Pulumi(pulumi),
 		auto.WorkDir(p.PathWorkingDir()),
 		auto.PulumiHome(global.ConfigDir()),
-		auto.Project(workspace.Project{
-			Name:    tokens.PackageName(p.app.Name),
-			Runtime: workspace.NewProjectRuntimeInfo("nodejs", nil),
-			Backend: &workspace.ProjectBackend{
-				URL: fmt.Sprintf("file://%v", p.PathWorkingDir()),
-			},
-			Main: outfile,
-		}),
+		auto.Project(settings),
 		auto.EnvVars(
 			env,
 		),
@@ -338,9 +304,8 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	}
 	providerShim = append(providerShim, fmt.Sprintf("import * as sst from \"%s\";", path.Join(p.PathPlatformDir(), "src/components")))
 
-	buildResult, err := js.Build(js.EvalOptions{
-		Dir:     p.PathRoot(),
-		Outfile: outfile,
+	build, err := p.buildConfig(input.Dev, js.EvalOptions{
+		Dir: p.PathRoot(),
 		Define: map[string]string{
 			"$app": string(appBytes),
 			"$cli": string(cliBytes),
@@ -364,23 +329,14 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 		})
 		return err
 	}
-	if !flag.SST_NO_CLEANUP {
-		defer js.Cleanup(buildResult)
-	}
+	defer build.cleanup()
 
-	var meta = map[string]interface{}{}
-	err = json.Unmarshal([]byte(buildResult.Metafile), &meta)
+	settings.Main = build.Outfile
+	err = ws.SaveProjectSettings(ctx, &settings)
 	if err != nil {
 		return err
 	}
-	files := []string{}
-	for key := range meta["inputs"].(map[string]interface{}) {
-		absPath, err := filepath.Abs(key)
-		if err != nil {
-			continue
-		}
-		files = append(files, absPath)
-	}
+	files := build.Files
 	bus.Publish(&BuildSuccessEvent{files})
 	slog.Info("tracked files")
 
@@ -416,9 +372,12 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	}
 	defer eventlog.Close()
 
-	errors := []Error{}
-	finished := false
-	importDiffs := map[string][]ImportDiff{}
+	commonErrors, err := p.CommonErrors()
+	if err != nil {
+		return err
+	}
+	state := newRunState(commonErrors)
+	state.requiredTags = p.app.RequiredTags
 
 	go func() {
 		for {
@@ -430,60 +389,22 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 					return
 				}
 
-				if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" {
-					if strings.HasPrefix(event.DiagnosticEvent.Message, "update failed") {
-						break
-					}
-					if strings.Contains(event.DiagnosticEvent.Message, "failed to register new resource") {
-						break
-					}
-
-					// check if the error is a common error
-					help := []string{}
-					for _, commonError := range CommonErrors {
-						if strings.Contains(event.DiagnosticEvent.Message, commonError.Message) {
-							help = append(help, commonError.Short...)
-						}
-					}
-
-					errors = append(errors, Error{
-						Message: event.DiagnosticEvent.Message,
-						URN:     event.DiagnosticEvent.URN,
-						Help:    help,
-					})
+				errorCount := len(state.errors)
+				fields, ok := state.process(event)
+				if !ok {
+					break
+				}
+				for _, err := range state.errors[errorCount:] {
 					telemetry.Track("cli.resource.error", map[string]interface{}{
-						"error": event.DiagnosticEvent.Message,
-						"urn":   event.DiagnosticEvent.URN,
+						"error": err.Message,
+						"urn":   err.URN,
 					})
 				}
 
-				if event.ResOpFailedEvent != nil {
-					if event.ResOpFailedEvent.Metadata.Op == apitype.OpImport {
-						for _, name := range event.ResOpFailedEvent.Metadata.Diffs {
-							old := event.ResOpFailedEvent.Metadata.Old.Inputs[name]
-							next := event.ResOpFailedEvent.Metadata.New.Inputs[name]
-							diffs, ok := importDiffs[event.ResOpFailedEvent.Metadata.URN]
-							if !ok {
-								diffs = []ImportDiff{}
-							}
-							importDiffs[event.ResOpFailedEvent.Metadata.URN] = append(diffs, ImportDiff{
-								URN:   event.ResOpFailedEvent.Metadata.URN,
-								Input: name,
-								Old:   old,
-								New:   next,
-							})
-						}
-					}
-				}
-
-				for _, field := range getNotNilFields(event) {
+				for _, field := range fields {
 					bus.Publish(field)
 				}
 
-				if event.SummaryEvent != nil {
-					finished = true
-				}
-
 				bytes, err := json.Marshal(event)
 				if err != nil {
 					return
@@ -501,10 +422,15 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 		if err != nil {
 			return
 		}
-		complete.Finished = finished
-		complete.Errors = errors
-		complete.ImportDiffs = importDiffs
+		complete.Finished = state.finished
+		complete.Errors = state.errors
+		complete.ImportDiffs = state.importDiffs
+		complete.Timings = state.timings.result()
 		defer bus.Publish(complete)
+		// sst dev doesn't check the tags before deploying so they are only warned about
+		if len(state.untagged) > 0 && (input.Command == "diff" || input.Dev) {
+			bus.Publish(&TagPolicyEvent{Resources: state.untagged})
+		}
 		if input.Command == "diff" {
 			return
 		}
@@ -517,6 +443,18 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	}()
 
 	slog.Info("running stack command", "cmd", input.Command)
+	// sst dev deploys on every change, which would flood the webhooks
+	notify := input.Command != "diff" && !input.Dev
+	if notify {
+		sendWebhooks(ctx, p.app.Webhooks, WebhookPayload{
+			Event:    "start",
+			App:      p.app.Name,
+			Stage:    p.app.Stage,
+			UpdateID: updateID,
+			Command:  input.Command,
+			Version:  p.Version(),
+		})
+	}
 	var summary auto.UpdateSummary
 	defer func() {
 		if input.Command == "diff" {
@@ -545,7 +483,7 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 				parsed.ResourceDeleted = match
 			}
 		}
-		for _, err := range errors {
+		for _, err := range state.errors {
 			parsed.Errors = append(parsed.Errors, provider.SummaryError{
 				URN:     err.URN,
 				Message: err.Message,
@@ -560,6 +498,24 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 			TimeStarted:   parsed.TimeStarted,
 			TimeCompleted: parsed.TimeCompleted,
 		})
+		if !notify {
+			return
+		}
+		event := "success"
+		if len(parsed.Errors) > 0 || !state.finished {
+			event = "failure"
+		}
+		sendWebhooks(context.Background(), p.app.Webhooks, WebhookPayload{
+			Event:    event,
+			App:      p.app.Name,
+			Stage:    p.app.Stage,
+			UpdateID: updateID,
+			Command:  input.Command,
+			Version:  parsed.Version,
+			Summary:  &parsed,
+			Errors:   parsed.Errors,
+			Duration: time.Since(started).Seconds(),
+		})
 	}()
 
 	pulumiLog, err := os.Create(p.PathLog("pulumi"))
@@ -606,16 +562,46 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 		}
 	}
 
+	planPath, err := p.checkPlan(ctx, input, stack, completed)
+	if planPath != "" {
+		defer os.Remove(planPath)
+	}
+	if err != nil {
+		state.errors = append(state.errors, Error{Message: err.Error()})
+		return ErrStackRunFailed
+	}
+
+	// hooks don't run for the deploys sst dev makes on every change
+	preHooks := map[string]string{
+		"deploy": p.app.Hooks.PreDeploy,
+		"remove": p.app.Hooks.PreRemove,
+	}
+	if !input.Dev {
+		err = p.runHook(ctx, "pre-"+input.Command, preHooks[input.Command], completed)
+		if err != nil {
+			state.errors = append(state.errors, Error{Message: err.Error()})
+			p.runFailureHook(ctx, input, stack, state.errors)
+			return ErrStackRunFailed
+		}
+	}
+
 	switch input.Command {
 	case "deploy":
-		result, derr := stack.Up(ctx,
+		opts := []optup.Option{
 			optup.DebugLogging(debugLogging),
 			optup.Target(input.Target),
 			optup.TargetDependents(),
 			optup.ProgressStreams(pulumiLog),
 			optup.ErrorProgressStreams(pulumiErrWriter),
 			optup.EventStreams(stream),
-		)
+		}
+		if planPath != "" {
+			opts = append(opts, optup.Plan(planPath))
+		}
+		result, derr := stack.Up(ctx, opts...)
+		if planPath != "" {
+			os.Remove(planPath)
+		}
 		err = derr
 		summary = result.Summary
 
@@ -655,13 +641,122 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	}
 
 	slog.Info("done running stack command")
+	if err == nil && input.Command == "deploy" && !input.Dev && p.app.Hooks.PostDeploy != "" {
+		complete, cerr := getCompletedEvent(ctx, stack)
+		if cerr != nil {
+			return cerr
+		}
+		err = p.runHook(ctx, "post-deploy", p.app.Hooks.PostDeploy, complete)
+		if err != nil {
+			state.errors = append(state.errors, Error{Message: err.Error()})
+		}
+	}
 	if err != nil {
 		slog.Error("stack run failed", "error", err)
+		p.runFailureHook(ctx, input, stack, state.errors)
 		return ErrStackRunFailed
 	}
 	return nil
 }
 
+// runFailureHook runs the on-failure hook with the errors from the run. The run
+// has already failed so if the hook fails too it is only logged.
+func (p *Project) runFailureHook(ctx context.Context, input *StackInput, stack auto.Stack, errs []Error) {
+	if p.app.Hooks.OnFailure == "" || input.Dev || (input.Command != "deploy" && input.Command != "remove") {
+		return
+	}
+	complete, err := getCompletedEvent(ctx, stack)
+	if err != nil {
+		slog.Error("failed to get outputs for hook", "err", err)
+		complete = &CompleteEvent{}
+	}
+	complete.Errors = errs
+	err = p.runHook(ctx, "on-failure", p.app.Hooks.OnFailure, complete)
+	if err != nil {
+		slog.Error("on-failure hook failed", "err", err)
+	}
+}
+
+type runState struct {
+	errors       []Error
+	finished     bool
+	importDiffs  map[string][]ImportDiff
+	timings      *timingRecorder
+	commonErrors []CommonError
+	requiredTags []string
+	untagged     []TagStatus
+}
+
+func newRunState(commonErrors []CommonError) *runState {
+	return &runState{
+		commonErrors: commonErrors,
+		errors:       []Error{},
+		importDiffs:  map[string][]ImportDiff{},
+		timings:      newTimingRecorder(),
+		untagged:     []TagStatus{},
+	}
+}
+
+// process tracks the errors, import diffs, and timings in an engine event and
+// returns the fields that should be published. Events that should be dropped
+// entirely return false.
+func (s *runState) process(event events.EngineEvent) ([]interface{}, bool) {
+	if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" {
+		if strings.HasPrefix(event.DiagnosticEvent.Message, "update failed") {
+			return nil, false
+		}
+		if strings.Contains(event.DiagnosticEvent.Message, "failed to register new resource") {
+			return nil, false
+		}
+
+		// check if the error is a common error
+		help, severity := matchCommonErrors(s.commonErrors, event.DiagnosticEvent.Message, event.DiagnosticEvent.URN)
+		s.errors = append(s.errors, Error{
+			Message:  event.DiagnosticEvent.Message,
+			URN:      event.DiagnosticEvent.URN,
+			Help:     help,
+			Severity: severity,
+		})
+	}
+
+	if event.ResourcePreEvent != nil {
+		s.timings.start(event.ResourcePreEvent.Metadata)
+		if status, ok := planTags(event.ResourcePreEvent.Metadata, s.requiredTags); ok {
+			s.untagged = append(s.untagged, status)
+		}
+	}
+
+	if event.ResOutputsEvent != nil {
+		s.timings.end(event.ResOutputsEvent.Metadata, false)
+	}
+
+	if event.ResOpFailedEvent != nil {
+		s.timings.end(event.ResOpFailedEvent.Metadata, true)
+		if event.ResOpFailedEvent.Metadata.Op == apitype.OpImport {
+			for _, name := range event.ResOpFailedEvent.Metadata.Diffs {
+				old := event.ResOpFailedEvent.Metadata.Old.Inputs[name]
+				next := event.ResOpFailedEvent.Metadata.New.Inputs[name]
+				diffs, ok := s.importDiffs[event.ResOpFailedEvent.Metadata.URN]
+				if !ok {
+					diffs = []ImportDiff{}
+				}
+				s.importDiffs[event.ResOpFailedEvent.Metadata.URN] = append(diffs, ImportDiff{
+					URN:   event.ResOpFailedEvent.Metadata.URN,
+					Input: name,
+					Old:   old,
+					New:   next,
+				})
+			}
+		}
+	}
+
+	if event.SummaryEvent != nil {
+		s.finished = true
+	}
+
+	return getNotNilFields(event), true
+}
+
 func (p *Project) Lock(updateID string, command string) error {
 	return provider.Lock(p.home, updateID, p.Version(), command, p.app.Name, p.app.Stage)
 }
@@ -790,6 +885,7 @@ func getCompletedEvent(ctx context.Context, stack auto.Stack) (*CompleteEvent, e
 		Errors:      []Error{},
 		Finished:    false,
 		Resources:   []apitype.ResourceV3{},
+		Timings:     []ResourceTiming{},
 	}
 	if len(deployment.Resources) == 0 {
 		return complete, nil
@@ -0,0 +1,127 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/ion/internal/util"
+)
+
+//go:generate ../../scripts/taggable
+
+// TagStatus is whether a resource carries the required tags. Resources that
+// do not support tags are not Taggable and cannot be checked.
+type TagStatus struct {
+	URN      string            `json:"urn"`
+	Type     string            `json:"type"`
+	Taggable bool              `json:"taggable"`
+	Tags     map[string]string `json:"tags"`
+	Missing  []string          `json:"missing"`
+}
+
+// TagPolicyEvent is published after a diff, or a deploy in sst dev, with the
+// resources that are missing required tags.
+type TagPolicyEvent struct {
+	Resources []TagStatus
+}
+
+// resourceTags merges the tags a resource is created with. AWS resources that
+// support tags have the default tags of their provider in tagsAll.
+func resourceTags(inputs, outputs map[string]interface{}) (map[string]string, bool) {
+	tags := map[string]string{}
+	found := false
+	for _, source := range []map[string]interface{}{inputs, outputs} {
+		for _, key := range []string{"tags", "tagsAll"} {
+			value, ok := source[key]
+			if !ok {
+				continue
+			}
+			found = true
+			if match, ok := value.(map[string]interface{}); ok {
+				for tag, tagValue := range match {
+					tags[tag] = fmt.Sprint(tagValue)
+				}
+			}
+		}
+	}
+	return tags, found
+}
+
+// taggable decides from the type whether an AWS resource supports tags, since
+// a new resource without any tags doesn't have them in its inputs. Resources
+// of other providers support tags if they have them.
+func taggable(kind string, found bool) bool {
+	if strings.HasPrefix(kind, "aws:") {
+		return awsTaggable[kind]
+	}
+	return found
+}
+
+func checkTags(urn, kind string, custom bool, inputs, outputs map[string]interface{}, required []string) (TagStatus, bool) {
+	if !custom || strings.HasPrefix(kind, "pulumi:") {
+		return TagStatus{}, false
+	}
+	tags, found := resourceTags(inputs, outputs)
+	status := TagStatus{
+		URN:      urn,
+		Type:     kind,
+		Taggable: taggable(kind, found),
+		Tags:     tags,
+		Missing:  []string{},
+	}
+	if status.Taggable {
+		for _, tag := range required {
+			if _, ok := tags[tag]; !ok {
+				status.Missing = append(status.Missing, tag)
+			}
+		}
+	}
+	return status, true
+}
+
+// TagReport lists every resource in the state and the required tags it is
+// missing.
+func (p *Project) TagReport(complete *CompleteEvent) []TagStatus {
+	result := []TagStatus{}
+	for _, resource := range complete.Resources {
+		status, ok := checkTags(string(resource.URN), string(resource.Type), resource.Custom, resource.Inputs, resource.Outputs, p.app.RequiredTags)
+		if ok {
+			result = append(result, status)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URN < result[j].URN
+	})
+	return result
+}
+
+// planTags checks the tags of a resource that a preview is about to create or
+// update.
+func planTags(metadata apitype.StepEventMetadata, required []string) (TagStatus, bool) {
+	if len(required) == 0 || metadata.New == nil {
+		return TagStatus{}, false
+	}
+	switch metadata.Op {
+	case apitype.OpCreate, apitype.OpUpdate, apitype.OpReplace, apitype.OpCreateReplacement, apitype.OpImport:
+	default:
+		return TagStatus{}, false
+	}
+	status, ok := checkTags(metadata.URN, metadata.Type, metadata.New.Custom, metadata.New.Inputs, metadata.New.Outputs, required)
+	if !ok || len(status.Missing) == 0 {
+		return TagStatus{}, false
+	}
+	return status, true
+}
+
+func (p *Project) checkRequiredTags(untagged []TagStatus) error {
+	if len(untagged) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("%v resources are missing required tags. Add them to the `defaultTags` of your provider or to the resources.", len(untagged))}
+	for _, status := range untagged {
+		lines = append(lines, fmt.Sprintf("   %v: %v", status.URN, strings.Join(status.Missing, ", ")))
+	}
+	return util.NewReadableError(nil, strings.Join(lines, "\n"))
+}
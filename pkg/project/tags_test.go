@@ -0,0 +1,85 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestTagReport(t *testing.T) {
+	p := &Project{app: &App{RequiredTags: []string{"owner", "cost-center"}}}
+	report := p.TagReport(&CompleteEvent{
+		Resources: []apitype.ResourceV3{
+			{
+				URN:     resource.URN("urn:pulumi:dev::app::aws:s3/bucketV2:BucketV2::Bucket"),
+				Type:    "aws:s3/bucketV2:BucketV2",
+				Custom:  true,
+				Outputs: map[string]interface{}{"tagsAll": map[string]interface{}{"owner": "infra", "sst:app": "app"}},
+			},
+			{
+				URN:    resource.URN("urn:pulumi:dev::app::aws:s3/bucketPolicy:BucketPolicy::Policy"),
+				Type:   "aws:s3/bucketPolicy:BucketPolicy",
+				Custom: true,
+			},
+			{
+				URN:  resource.URN("urn:pulumi:dev::app::sst:aws:Bucket::Assets"),
+				Type: "sst:aws:Bucket",
+			},
+		},
+	})
+	if len(report) != 2 {
+		t.Fatalf("expected components to be skipped, got %v", report)
+	}
+	if report[0].Taggable {
+		t.Fatalf("expected the policy to not support tags")
+	}
+	if !report[1].Taggable || len(report[1].Missing) != 1 || report[1].Missing[0] != "cost-center" {
+		t.Fatalf("unexpected status %+v", report[1])
+	}
+}
+
+func TestPlanTags(t *testing.T) {
+	required := []string{"owner"}
+	step := func(kind string, inputs map[string]interface{}) apitype.StepEventMetadata {
+		return apitype.StepEventMetadata{
+			Op:   apitype.OpCreate,
+			URN:  "urn:pulumi:dev::app::" + kind + "::Resource",
+			Type: kind,
+			New:  &apitype.StepEventStateMetadata{Type: kind, Custom: true, Inputs: inputs},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		metadata apitype.StepEventMetadata
+		untagged bool
+	}{
+		{"new bucket without any tags", step("aws:s3/bucketV2:BucketV2", map[string]interface{}{"bucket": "assets"}), true},
+		{"new bucket with the tag", step("aws:s3/bucketV2:BucketV2", map[string]interface{}{"tags": map[string]interface{}{"owner": "infra"}}), false},
+		{"policy that does not support tags", step("aws:s3/bucketPolicy:BucketPolicy", map[string]interface{}{}), false},
+		{"other provider without tags", step("cloudflare:index/record:Record", map[string]interface{}{}), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, untagged := planTags(test.metadata, required)
+			if untagged != test.untagged {
+				t.Fatalf("expected untagged to be %v, got %+v", test.untagged, status)
+			}
+			if untagged && (!status.Taggable || len(status.Missing) != 1 || status.Missing[0] != "owner") {
+				t.Fatalf("unexpected status %+v", status)
+			}
+		})
+	}
+}
+
+func TestCheckPlanDev(t *testing.T) {
+	p := &Project{app: &App{Stage: "dev", RequiredTags: []string{"owner"}}}
+	// the zero stack would fail if a preview was run
+	planPath, err := p.checkPlan(context.Background(), &StackInput{Command: "deploy", Dev: true}, auto.Stack{}, &CompleteEvent{})
+	if err != nil || planPath != "" {
+		t.Fatalf("expected sst dev to deploy without a preview, got %v %v", planPath, err)
+	}
+}
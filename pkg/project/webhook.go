@@ -0,0 +1,154 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+// AppWebhook is a URL that gets a POST when a deploy starts, succeeds, or
+// fails. The format is one of json, slack, or teams.
+type AppWebhook struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+	Format string   `json:"format,omitempty"`
+}
+
+type WebhookPayload struct {
+	Event    string                  `json:"event"`
+	App      string                  `json:"app"`
+	Stage    string                  `json:"stage"`
+	UpdateID string                  `json:"updateID"`
+	Command  string                  `json:"command"`
+	Version  string                  `json:"version"`
+	Summary  *provider.Summary       `json:"summary,omitempty"`
+	Errors   []provider.SummaryError `json:"errors"`
+	Duration float64                 `json:"duration"`
+}
+
+const webhookTimeout = 10 * time.Second
+
+var webhookEvents = []string{"start", "success", "failure"}
+var webhookFormats = []string{"json", "slack", "teams"}
+
+func validateWebhooks(webhooks []AppWebhook) error {
+	for _, webhook := range webhooks {
+		if webhook.URL == "" {
+			return fmt.Errorf("Webhooks must have a url")
+		}
+		if webhook.Format != "" && !slices.Contains(webhookFormats, webhook.Format) {
+			return fmt.Errorf("Webhook format must be one of: %v", strings.Join(webhookFormats, ", "))
+		}
+		for _, event := range webhook.Events {
+			if !slices.Contains(webhookEvents, event) {
+				return fmt.Errorf("Webhook events must be one of: %v", strings.Join(webhookEvents, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// sendWebhooks posts the payload to every webhook that listens to the event.
+// Webhooks are best effort so failures are only logged.
+func sendWebhooks(ctx context.Context, webhooks []AppWebhook, payload WebhookPayload) {
+	if payload.Errors == nil {
+		payload.Errors = []provider.SummaryError{}
+	}
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		if len(webhook.Events) > 0 && !slices.Contains(webhook.Events, payload.Event) {
+			continue
+		}
+		wg.Add(1)
+		go func(webhook AppWebhook) {
+			defer wg.Done()
+			err := sendWebhook(ctx, webhook, payload)
+			if err != nil {
+				slog.Error("failed to send webhook", "url", webhook.URL, "err", err)
+			}
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+func sendWebhook(ctx context.Context, webhook AppWebhook, payload WebhookPayload) error {
+	body, err := webhookBody(webhook.Format, payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sst/"+payload.Version)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %v", resp.Status)
+	}
+	slog.Info("sent webhook", "url", webhook.URL, "event", payload.Event)
+	return nil
+}
+
+func webhookBody(format string, payload WebhookPayload) ([]byte, error) {
+	title, lines := webhookMessage(payload)
+	switch format {
+	case "slack":
+		return json.Marshal(map[string]interface{}{
+			"text": title + "\n" + strings.Join(lines, "\n"),
+		})
+	case "teams":
+		colors := map[string]string{"start": "0078D7", "success": "2EB67D", "failure": "E01E5A"}
+		return json.Marshal(map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "http://schema.org/extensions",
+			"summary":    title,
+			"themeColor": colors[payload.Event],
+			"title":      title,
+			"text":       strings.Join(lines, "\n\n"),
+		})
+	default:
+		return json.Marshal(payload)
+	}
+}
+
+func webhookMessage(payload WebhookPayload) (string, []string) {
+	verbs := map[string]string{"start": "started", "success": "succeeded", "failure": "failed"}
+	title := fmt.Sprintf("sst %v %v for %v / %v", payload.Command, verbs[payload.Event], payload.App, payload.Stage)
+	lines := []string{fmt.Sprintf("Update %v", payload.UpdateID)}
+	if payload.Event == "start" {
+		return title, lines
+	}
+	lines = append(lines, fmt.Sprintf("Took %v", (time.Duration(payload.Duration*float64(time.Second))).Round(time.Second)))
+	if payload.Summary != nil {
+		lines = append(lines, fmt.Sprintf("%v created, %v updated, %v deleted, %v unchanged",
+			payload.Summary.ResourceCreated,
+			payload.Summary.ResourceUpdated,
+			payload.Summary.ResourceDeleted,
+			payload.Summary.ResourceSame,
+		))
+	}
+	for _, err := range payload.Errors {
+		if err.URN != "" {
+			lines = append(lines, fmt.Sprintf("%v: %v", err.URN, err.Message))
+			continue
+		}
+		lines = append(lines, err.Message)
+	}
+	return title, lines
+}
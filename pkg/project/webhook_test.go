@@ -0,0 +1,59 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sst/ion/pkg/project/provider"
+)
+
+func TestSendWebhooks(t *testing.T) {
+	var lock sync.Mutex
+	bodies := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		bodies[r.URL.Path] = body
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	sendWebhooks(context.Background(), []AppWebhook{
+		{URL: server.URL + "/json"},
+		{URL: server.URL + "/slack", Format: "slack"},
+		{URL: server.URL + "/start", Events: []string{"start"}},
+	}, WebhookPayload{
+		Event:    "failure",
+		App:      "app",
+		Stage:    "production",
+		UpdateID: "123",
+		Command:  "deploy",
+		Summary:  &provider.Summary{ResourceCreated: 2},
+		Errors:   []provider.SummaryError{{URN: "urn", Message: "boom"}},
+		Duration: 12,
+	})
+
+	if _, ok := bodies["/start"]; ok {
+		t.Fatal("expected the start webhook to be skipped")
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(bodies["/json"], &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Stage != "production" || payload.Summary.ResourceCreated != 2 || len(payload.Errors) != 1 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	var slack map[string]string
+	if err := json.Unmarshal(bodies["/slack"], &slack); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(slack["text"], "failed for app / production") || !strings.Contains(slack["text"], "boom") {
+		t.Fatalf("unexpected slack message %v", slack["text"])
+	}
+}
@@ -0,0 +1,59 @@
+package lambda
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed events/*.json
+var events embed.FS
+
+// EventTemplates returns the names of the sample events.
+func EventTemplates() []string {
+	entries, _ := events.ReadDir("events")
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Event returns the sample event with the given name. If a payload is passed
+// in, it is merged on top of the sample.
+func Event(template string, payload []byte) ([]byte, error) {
+	data, err := events.ReadFile("events/" + template + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("Unknown event template %q, use one of: %s", template, strings.Join(EventTemplates(), ", "))
+	}
+	if len(payload) == 0 {
+		return data, nil
+	}
+	var base interface{}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	var override interface{}
+	if err := json.Unmarshal(payload, &override); err != nil {
+		return nil, fmt.Errorf("Payload is not valid JSON: %w", err)
+	}
+	return json.Marshal(merge(base, override))
+}
+
+func merge(base interface{}, override interface{}) interface{} {
+	baseMap, ok := base.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	overrideMap, ok := override.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	for key, value := range overrideMap {
+		baseMap[key] = merge(baseMap[key], value)
+	}
+	return baseMap
+}
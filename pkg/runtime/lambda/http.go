@@ -0,0 +1,122 @@
+package lambda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// HTTPEvent converts a request into an API Gateway v2 event, the same format
+// function URLs use.
+func HTTPEvent(r *http.Request, path string) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{}
+	cookies := []string{}
+	for name, values := range r.Header {
+		if strings.EqualFold(name, "Cookie") {
+			for _, value := range values {
+				for _, cookie := range strings.Split(value, ";") {
+					cookies = append(cookies, strings.TrimSpace(cookie))
+				}
+			}
+			continue
+		}
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	query := map[string]string{}
+	for name, values := range r.URL.Query() {
+		query[name] = strings.Join(values, ",")
+	}
+	if path == "" {
+		path = "/"
+	}
+	now := time.Now()
+	event := map[string]interface{}{
+		"version":               "2.0",
+		"routeKey":              "$default",
+		"rawPath":               path,
+		"rawQueryString":        r.URL.RawQuery,
+		"headers":               headers,
+		"queryStringParameters": query,
+		"requestContext": map[string]interface{}{
+			"accountId":  "000000000000",
+			"apiId":      "local",
+			"domainName": r.Host,
+			"http": map[string]interface{}{
+				"method":    r.Method,
+				"path":      path,
+				"protocol":  r.Proto,
+				"sourceIp":  sourceIP(r.RemoteAddr),
+				"userAgent": r.UserAgent(),
+			},
+			"requestId": NewRequestID(),
+			"routeKey":  "$default",
+			"stage":     "$default",
+			"time":      now.Format("02/Jan/2006:15:04:05 -0700"),
+			"timeEpoch": now.UnixMilli(),
+		},
+		"isBase64Encoded": false,
+	}
+	if len(cookies) > 0 {
+		event["cookies"] = cookies
+	}
+	if len(body) > 0 {
+		if utf8.Valid(body) {
+			event["body"] = string(body)
+		} else {
+			event["body"] = base64.StdEncoding.EncodeToString(body)
+			event["isBase64Encoded"] = true
+		}
+	}
+	return json.Marshal(event)
+}
+
+// WriteHTTPResponse writes the output of a function as an HTTP response. Like
+// function URLs, an output without a statusCode is returned as JSON.
+func WriteHTTPResponse(w http.ResponseWriter, output []byte) {
+	var response struct {
+		StatusCode      int               `json:"statusCode"`
+		Headers         map[string]string `json:"headers"`
+		Cookies         []string          `json:"cookies"`
+		Body            string            `json:"body"`
+		IsBase64Encoded bool              `json:"isBase64Encoded"`
+	}
+	if json.Unmarshal(output, &response) != nil || response.StatusCode == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+		return
+	}
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	for _, cookie := range response.Cookies {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err == nil {
+			body = decoded
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+	w.Write(body)
+}
+
+// sourceIP returns the host of an address like 127.0.0.1:1234 or [::1]:1234.
+func sourceIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
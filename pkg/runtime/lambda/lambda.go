@@ -0,0 +1,290 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sst/ion/internal/util"
+)
+
+// Server is a local implementation of the Lambda Runtime API. Workers poll it
+// for invocations under /{workerID}/runtime/..., the same paths they use when
+// AWS_LAMBDA_RUNTIME_API points at the dev server.
+type Server struct {
+	mu      sync.Mutex
+	workers map[string]*worker
+}
+
+type Invocation struct {
+	RequestID string
+	Payload   []byte
+	Deadline  time.Time
+	// Headers are passed along to the worker with the invocation, like the
+	// trace ID or client context of an invocation that came from AWS.
+	Headers http.Header
+	result  chan *Result
+}
+
+type Result struct {
+	RequestID string
+	Output    []byte
+	Error     *Error
+	// Headers are the headers the worker sent with its response that matter
+	// when passing it along, like the response mode of a streaming function.
+	Headers http.Header
+}
+
+type Error struct {
+	ErrorType    string   `json:"errorType"`
+	ErrorMessage string   `json:"errorMessage"`
+	Trace        []string `json:"trace"`
+}
+
+type worker struct {
+	functionID string
+	pending    chan *Invocation
+	active     map[string]*Invocation
+	initError  *Error
+}
+
+func New() *Server {
+	return &Server{
+		workers: map[string]*worker{},
+	}
+}
+
+// Register adds a worker before it starts so invocations can be queued for it.
+func (s *Server) Register(workerID string, functionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.workers[workerID]; ok {
+		return
+	}
+	s.workers[workerID] = &worker{
+		functionID: functionID,
+		pending:    make(chan *Invocation, 100),
+		active:     map[string]*Invocation{},
+	}
+}
+
+// Shutdown fails the invocations of a worker that exited and forgets it.
+func (s *Server) Shutdown(workerID string) {
+	s.Exit(workerID, &Error{
+		ErrorType:    "Runtime.ExitError",
+		ErrorMessage: "Runtime exited without providing a reason",
+	})
+}
+
+// Exit is like Shutdown when it's known why the worker exited, like running
+// out of memory.
+func (s *Server) Exit(workerID string, err *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[workerID]
+	delete(s.workers, workerID)
+	if !ok {
+		return
+	}
+	if w.initError != nil {
+		err = w.initError
+	}
+	w.fail(err)
+}
+
+// fail completes every queued and active invocation with the error. It must be
+// called with the server lock held.
+func (w *worker) fail(err *Error) {
+	for {
+		select {
+		case invocation := <-w.pending:
+			invocation.complete(&Result{RequestID: invocation.RequestID, Error: err})
+		default:
+			for id, invocation := range w.active {
+				invocation.complete(&Result{RequestID: id, Error: err})
+				delete(w.active, id)
+			}
+			return
+		}
+	}
+}
+
+func (i *Invocation) complete(result *Result) {
+	select {
+	case i.result <- result:
+	default:
+	}
+}
+
+// NewRequestID returns a random ID in the format of Lambda request IDs.
+func NewRequestID() string {
+	return util.RandomString(8) + "-" + util.RandomString(4) + "-" + util.RandomString(4) + "-" + util.RandomString(4) + "-" + util.RandomString(12)
+}
+
+// Invoke queues the payload for the worker and waits for its response. It
+// returns a timeout error if the worker does not respond in time.
+func (s *Server) Invoke(ctx context.Context, workerID string, requestID string, payload []byte, timeout time.Duration) (*Result, error) {
+	return s.Send(ctx, workerID, &Invocation{
+		RequestID: requestID,
+		Payload:   payload,
+		Deadline:  time.Now().Add(timeout),
+	})
+}
+
+// Send queues an invocation for the worker and waits for its response until
+// the deadline of the invocation.
+func (s *Server) Send(ctx context.Context, workerID string, invocation *Invocation) (*Result, error) {
+	s.mu.Lock()
+	w, ok := s.workers[workerID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("worker %s is not registered", workerID)
+	}
+	timeout := time.Until(invocation.Deadline)
+	invocation.result = make(chan *Result, 1)
+	s.mu.Lock()
+	initError := w.initError
+	s.mu.Unlock()
+	if initError != nil {
+		return &Result{RequestID: invocation.RequestID, Error: initError}, nil
+	}
+	timedOut := &Result{
+		RequestID: invocation.RequestID,
+		Error: &Error{
+			ErrorType:    "Sandbox.Timedout",
+			ErrorMessage: fmt.Sprintf("Task timed out after %.2f seconds", timeout.Seconds()),
+		},
+	}
+	// the deadline counts from when the invocation is sent, including the time
+	// it waits for the worker to pick it up
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case w.pending <- invocation:
+	case <-timer.C:
+		return timedOut, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case result := <-invocation.result:
+		return result, nil
+	case <-timer.C:
+		s.mu.Lock()
+		delete(w.active, invocation.RequestID)
+		s.mu.Unlock()
+		return timedOut, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 3 || path[1] != "runtime" {
+		writeError(w, http.StatusNotFound, "InvalidRequest", "Unknown path "+r.URL.Path)
+		return
+	}
+	workerID := path[0]
+	s.mu.Lock()
+	worker, ok := s.workers[workerID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "InvalidWorker", "Unknown worker "+workerID)
+		return
+	}
+	route := path[2:]
+	slog.Info("runtime api request", "workerID", workerID, "path", route)
+	switch {
+	case r.Method == http.MethodGet && len(route) == 2 && route[0] == "invocation" && route[1] == "next":
+		s.next(w, r, worker)
+	case r.Method == http.MethodPost && len(route) == 3 && route[0] == "invocation" && route[2] == "response":
+		body, _ := io.ReadAll(r.Body)
+		headers := http.Header{}
+		for _, name := range []string{"Content-Type", "Lambda-Runtime-Function-Response-Mode"} {
+			if value := r.Header.Get(name); value != "" {
+				headers.Set(name, value)
+			}
+		}
+		s.respond(w, worker, route[1], &Result{RequestID: route[1], Output: body, Headers: headers})
+	case r.Method == http.MethodPost && len(route) == 3 && route[0] == "invocation" && route[2] == "error":
+		s.respond(w, worker, route[1], &Result{RequestID: route[1], Error: readError(r)})
+	case r.Method == http.MethodPost && len(route) == 2 && route[0] == "init" && route[1] == "error":
+		err := readError(r)
+		s.mu.Lock()
+		worker.initError = err
+		worker.fail(err)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusNotFound, "InvalidRequest", "Unknown path "+r.URL.Path)
+	}
+}
+
+func (s *Server) next(w http.ResponseWriter, r *http.Request, worker *worker) {
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case invocation := <-worker.pending:
+			if time.Now().After(invocation.Deadline) {
+				continue
+			}
+			s.mu.Lock()
+			worker.active[invocation.RequestID] = invocation
+			s.mu.Unlock()
+			for name, values := range invocation.Headers {
+				w.Header()[name] = values
+			}
+			w.Header().Set("Lambda-Runtime-Aws-Request-Id", invocation.RequestID)
+			w.Header().Set("Lambda-Runtime-Deadline-Ms", strconv.FormatInt(invocation.Deadline.UnixMilli(), 10))
+			if w.Header().Get("Lambda-Runtime-Invoked-Function-Arn") == "" {
+				w.Header().Set("Lambda-Runtime-Invoked-Function-Arn", "arn:aws:lambda:local:000000000000:function:"+worker.functionID)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write(invocation.Payload)
+			return
+		}
+	}
+}
+
+func (s *Server) respond(w http.ResponseWriter, worker *worker, requestID string, result *Result) {
+	s.mu.Lock()
+	invocation, ok := worker.active[requestID]
+	delete(worker.active, requestID)
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusBadRequest, "InvalidRequestID", "Invalid request ID "+requestID)
+		return
+	}
+	invocation.complete(result)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func readError(r *http.Request) *Error {
+	result := &Error{
+		ErrorType:    r.Header.Get("Lambda-Runtime-Function-Error-Type"),
+		ErrorMessage: "Unknown error",
+	}
+	body, _ := io.ReadAll(r.Body)
+	json.Unmarshal(body, result)
+	return result
+}
+
+func writeError(w http.ResponseWriter, status int, errorType string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&Error{
+		ErrorType:    errorType,
+		ErrorMessage: message,
+	})
+}
@@ -0,0 +1,162 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func next(t *testing.T, url string) (string, string) {
+	resp, err := http.Get(url + "/runtime/invocation/next")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.Header.Get("Lambda-Runtime-Aws-Request-Id"), string(body)
+}
+
+func post(t *testing.T, url string, body string) int {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestInvoke(t *testing.T) {
+	api := New()
+	server := httptest.NewServer(api)
+	defer server.Close()
+	api.Register("worker", "MyFunction")
+	url := server.URL + "/worker"
+
+	go func() {
+		requestID, body := next(t, url)
+		post(t, url+"/runtime/invocation/"+requestID+"/response", `{"echo":`+body+`}`)
+		requestID, _ = next(t, url)
+		post(t, url+"/runtime/invocation/"+requestID+"/error", `{"errorType":"Error","errorMessage":"boom"}`)
+	}()
+
+	result, err := api.Invoke(context.Background(), "worker", NewRequestID(), []byte(`{"id":1}`), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error != nil || string(result.Output) != `{"echo":{"id":1}}` {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	result, err = api.Invoke(context.Background(), "worker", NewRequestID(), []byte(`{}`), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == nil || result.Error.ErrorMessage != "boom" {
+		t.Fatalf("expected error, got %+v", result)
+	}
+
+	if status := post(t, url+"/runtime/invocation/unknown/response", `{}`); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown request, got %v", status)
+	}
+}
+
+func TestInvokeTimeout(t *testing.T) {
+	api := New()
+	api.Register("worker", "MyFunction")
+	result, err := api.Invoke(context.Background(), "worker", NewRequestID(), []byte(`{}`), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == nil || result.Error.ErrorType != "Sandbox.Timedout" {
+		t.Fatalf("expected timeout, got %+v", result)
+	}
+}
+
+func TestSendQueueFull(t *testing.T) {
+	api := New()
+	api.Register("worker", "MyFunction")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// nothing picks up the invocations so the queue fills up
+	for i := 0; i < 100; i++ {
+		go api.Invoke(ctx, "worker", NewRequestID(), []byte(`{}`), time.Minute)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	result, err := api.Invoke(context.Background(), "worker", NewRequestID(), []byte(`{}`), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == nil || result.Error.ErrorType != "Sandbox.Timedout" {
+		t.Fatalf("expected timeout while queued, got %+v", result)
+	}
+
+	cancelled, cancelSend := context.WithCancel(context.Background())
+	cancelSend()
+	_, err = api.Invoke(cancelled, "worker", NewRequestID(), []byte(`{}`), time.Minute)
+	if err != context.Canceled {
+		t.Fatalf("expected cancelled send, got %v", err)
+	}
+}
+
+func TestInitError(t *testing.T) {
+	api := New()
+	server := httptest.NewServer(api)
+	defer server.Close()
+	api.Register("worker", "MyFunction")
+
+	status := post(t, server.URL+"/worker/runtime/init/error", `{"errorType":"Error","errorMessage":"bad import"}`)
+	if status != http.StatusAccepted {
+		t.Fatalf("expected 202, got %v", status)
+	}
+	result, err := api.Invoke(context.Background(), "worker", NewRequestID(), []byte(`{}`), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == nil || result.Error.ErrorMessage != "bad import" {
+		t.Fatalf("expected init error, got %+v", result)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	data, err := Event("sqs", []byte(`{"extra":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"extra":true`) || !strings.Contains(string(data), `"aws:sqs"`) {
+		t.Fatalf("expected merged event, got %s", data)
+	}
+	if _, err := Event("unknown", nil); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+}
+
+func TestHTTPEventSourceIP(t *testing.T) {
+	for addr, expected := range map[string]string{
+		"127.0.0.1:1234": "127.0.0.1",
+		"[::1]:1234":     "::1",
+	} {
+		r := httptest.NewRequest("GET", "/users", nil)
+		r.RemoteAddr = addr
+		data, err := HTTPEvent(r, "/users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var event struct {
+			RequestContext struct {
+				HTTP struct {
+					SourceIP string `json:"sourceIp"`
+				} `json:"http"`
+			} `json:"requestContext"`
+		}
+		json.Unmarshal(data, &event)
+		if event.RequestContext.HTTP.SourceIP != expected {
+			t.Fatalf("expected %v, got %v", expected, event.RequestContext.HTTP.SourceIP)
+		}
+	}
+}
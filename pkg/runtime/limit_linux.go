@@ -0,0 +1,24 @@
+package runtime
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+)
+
+// LimitMemory makes the command run with its data segment, and that of the
+// processes it starts, capped at the memory of the function. It's started
+// through prlimit so the limit is in place before anything is allocated, and
+// allocations past it fail the same way they would in Lambda.
+func LimitMemory(cmd *exec.Cmd, memory int) {
+	if memory <= 0 || cmd.Err != nil {
+		return
+	}
+	prlimit, err := exec.LookPath("prlimit")
+	if err != nil {
+		slog.Warn("prlimit not found, not limiting worker memory")
+		return
+	}
+	cmd.Args = append([]string{prlimit, fmt.Sprintf("--data=%d", memory<<20), "--", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = prlimit
+}
@@ -0,0 +1,24 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLimitMemory(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	collection := NewCollection("", &shellRuntime{script: `ulimit -d; echo $AWS_LAMBDA_FUNCTION_MEMORY_SIZE`})
+	worker, err := collection.Run(context.Background(), &RunInput{FunctionID: "Api", Memory: 256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, _ := io.ReadAll(worker.Logs())
+	if lines := strings.Fields(string(output)); len(lines) != 2 || lines[0] != "262144" || lines[1] != "256" {
+		t.Fatalf("expected a 256MB limit, got %q", output)
+	}
+}
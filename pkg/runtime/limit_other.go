@@ -0,0 +1,8 @@
+//go:build !linux
+
+package runtime
+
+import "os/exec"
+
+// LimitMemory is only supported on Linux.
+func LimitMemory(cmd *exec.Cmd, memory int) {}
@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -88,6 +89,10 @@ func (w *Worker) Stop() {
 	util.TerminateProcess(w.cmd.Process.Pid)
 }
 
+func (w *Worker) Wait() error {
+	return w.cmd.Wait()
+}
+
 func (w *Worker) Logs() io.ReadCloser {
 	reader, writer := io.Pipe()
 
@@ -126,10 +131,15 @@ type NodeProperties struct {
 var NODE_EXTENSIONS = []string{".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
 
 func (r *Runtime) Run(ctx context.Context, input *runtime.RunInput) (runtime.Worker, error) {
-	cmd := exec.CommandContext(
-		ctx,
-		"node",
-		"--enable-source-maps",
+	args := []string{"--enable-source-maps"}
+	if input.Inspect > 0 {
+		args = append(args, fmt.Sprintf("--inspect=127.0.0.1:%d", input.Inspect))
+	}
+	if input.Memory > 0 {
+		// like lambda, leave some of the memory for everything besides the heap
+		args = append(args, fmt.Sprintf("--max-old-space-size=%d", input.Memory*9/10))
+	}
+	args = append(args,
 		filepath.Join(
 			path.ResolvePlatformDir(input.CfgPath),
 			"/dist/nodejs-runtime/index.js",
@@ -137,6 +147,7 @@ func (r *Runtime) Run(ctx context.Context, input *runtime.RunInput) (runtime.Wor
 		filepath.Join(input.Build.Out, input.Build.Handler),
 		input.WorkerID,
 	)
+	cmd := exec.CommandContext(ctx, "node", args...)
 	util.SetProcessGroupID(cmd)
 	util.SetProcessCancel(cmd)
 	cmd.Env = input.Env
@@ -145,9 +156,12 @@ func (r *Runtime) Run(ctx context.Context, input *runtime.RunInput) (runtime.Wor
 	cmd.Env = append(cmd.Env, "AWS_LAMBDA_RUNTIME_API="+input.Server)
 	slog.Info("starting worker", "env", cmd.Env, "args", cmd.Args)
 	cmd.Dir = input.Build.Out
+	runtime.LimitMemory(cmd, input.Memory)
 	stdout, _ := cmd.StdoutPipe()
 	stderr, _ := cmd.StderrPipe()
-	cmd.Start()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
 	return &Worker{
 		stdout,
 		stderr,
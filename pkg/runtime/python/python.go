@@ -30,6 +30,10 @@ func (w *Worker) Stop() {
 	util.TerminateProcess(w.cmd.Process.Pid)
 }
 
+func (w *Worker) Wait() error {
+	return w.cmd.Wait()
+}
+
 func (w *Worker) Logs() io.ReadCloser {
 	reader, writer := io.Pipe()
 
@@ -333,6 +337,14 @@ func (r *PythonRuntime) Run(ctx context.Context, input *runtime.RunInput) (runti
 		}
 	}
 
+	if input.Inspect > 0 {
+		args = append(args,
+			"--with", "debugpy",
+			"python", "-m", "debugpy",
+			"--listen", fmt.Sprintf("127.0.0.1:%d", input.Inspect),
+		)
+	}
+
 	args = append(args,
 		filepath.Join(path.ResolvePlatformDir(input.CfgPath), "/dist/python-runtime/index.py"),
 		filepath.Join(input.Build.Out, input.Build.Handler),
@@ -352,6 +364,7 @@ func (r *PythonRuntime) Run(ctx context.Context, input *runtime.RunInput) (runti
 	cmd.Env = append(input.Env, "AWS_LAMBDA_RUNTIME_API="+input.Server)
 	slog.Info("starting worker", "env", cmd.Env, "args", cmd.Args)
 	cmd.Dir = input.Build.Out
+	runtime.LimitMemory(cmd, input.Memory)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdout pipe: %v", err)
@@ -360,7 +373,9 @@ func (r *PythonRuntime) Run(ctx context.Context, input *runtime.RunInput) (runti
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stderr pipe: %v", err)
 	}
-	cmd.Start()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
 	return &Worker{
 		stdout,
 		stderr,
@@ -7,11 +7,16 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/sst/ion/pkg/bus"
+	"github.com/sst/ion/pkg/flag"
 	"github.com/sst/ion/pkg/project/path"
 )
 
@@ -27,6 +32,24 @@ type Worker interface {
 	Logs() io.ReadCloser
 }
 
+// Process is implemented by workers that run as a process on this machine, so
+// it's known why they exited.
+type Process interface {
+	// Wait waits for the process to exit. It's called once the logs have been
+	// read to the end.
+	Wait() error
+}
+
+// ExitError is why a worker exited, with the error types Lambda uses.
+type ExitError struct {
+	Type    string
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return e.Type + ": " + e.Message
+}
+
 type BuildInput struct {
 	CfgPath       string
 	Dev           bool                       `json:"dev"`
@@ -65,12 +88,29 @@ type RunInput struct {
 	WorkerID   string
 	Build      *BuildOutput
 	Env        []string
+	// Inspect is the port a debugger should listen on, or 0 to not start one.
+	Inspect int
+	// Memory is the memory of the function in MB. Workers are limited to it
+	// where that's supported, or 0 to not limit them.
+	Memory int
+}
+
+// InspectEvent is published when a worker is started with a debugger that can
+// be attached to.
+type InspectEvent struct {
+	FunctionID string
+	WorkerID   string
+	Debugger   string
+	Address    string
 }
 
 type Collection struct {
 	runtimes []Runtime
 	cfgPath  string
 	targets  map[string]*BuildInput
+
+	inspectAll bool
+	inspect    map[string]bool
 }
 
 func NewCollection(platform string, runtimes ...Runtime) *Collection {
@@ -200,7 +240,104 @@ func (c *Collection) Run(ctx context.Context, input *RunInput) (Worker, error) {
 	if !ok {
 		return nil, fmt.Errorf("runtime not found")
 	}
-	return runtime.Run(ctx, input)
+	if flag.SST_DEV_NO_MEMORY_LIMIT {
+		input.Memory = 0
+	}
+	if input.Memory > 0 {
+		input.Env = append(input.Env, fmt.Sprintf("AWS_LAMBDA_FUNCTION_MEMORY_SIZE=%d", input.Memory))
+	}
+	inspecting := c.Inspecting(input.FunctionID)
+	if inspecting {
+		input.Inspect = freePort(InspectPort(input.FunctionID))
+	}
+	worker, err := runtime.Run(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if inspecting {
+		debugger := "node"
+		if strings.HasPrefix(input.Runtime, "python") {
+			debugger = "debugpy"
+		}
+		bus.Publish(&InspectEvent{
+			FunctionID: input.FunctionID,
+			WorkerID:   input.WorkerID,
+			Debugger:   debugger,
+			Address:    fmt.Sprintf("127.0.0.1:%d", input.Inspect),
+		})
+	}
+	return worker, nil
+}
+
+// OutOfMemory reports whether a line of a worker's output shows it ran out of
+// memory, either V8 reaching its heap limit or a Python MemoryError.
+func OutOfMemory(line string) bool {
+	return strings.Contains(line, "heap limit") ||
+		strings.Contains(line, "JavaScript heap out of memory") ||
+		strings.HasPrefix(strings.TrimSpace(line), "MemoryError")
+}
+
+// Exited waits for a worker to exit after its logs have been read to the end,
+// and describes why like Lambda does when a runtime exits during an
+// invocation. outOfMemory is whether OutOfMemory matched any of the logs. It
+// returns nil if that's not known for the worker.
+func Exited(worker Worker, outOfMemory bool) *ExitError {
+	process, ok := worker.(Process)
+	if !ok {
+		return nil
+	}
+	err := process.Wait()
+	if err == nil {
+		return &ExitError{
+			Type:    "Runtime.ExitError",
+			Message: "Runtime exited without providing a reason",
+		}
+	}
+	result := &ExitError{
+		Type:    "Runtime.ExitError",
+		Message: "Runtime exited with error: " + err.Error(),
+	}
+	if outOfMemory {
+		result.Type = "Runtime.OutOfMemory"
+	}
+	return result
+}
+
+// Inspect starts the workers of the functions with a debugger listening, or
+// the workers of every function if none are passed in.
+func (c *Collection) Inspect(functions ...string) {
+	c.inspectAll = len(functions) == 0
+	c.inspect = map[string]bool{}
+	for _, functionID := range functions {
+		c.inspect[functionID] = true
+	}
+}
+
+func (c *Collection) Inspecting(functionID string) bool {
+	return c.inspectAll || c.inspect[functionID]
+}
+
+// InspectPort is the port the debugger of a function listens on. It's derived
+// from the function's name so it stays the same across restarts and debugger
+// configs can be saved.
+func InspectPort(functionID string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(functionID))
+	return 9230 + int(hash.Sum32()%700)
+}
+
+// freePort returns the port if nothing is listening on it, otherwise the next
+// one that's free. This happens when a function has more than one worker.
+func freePort(port int) int {
+	for next := port; next < port+100; next++ {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", next))
+		if err != nil {
+			continue
+		}
+		listener.Close()
+		return next
+	}
+	return port
 }
 
 func (c *Collection) ShouldRebuild(runtime string, functionID string, file string) bool {
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/sst/ion/pkg/bus"
+)
+
+type fakeRuntime struct {
+	inputs []*RunInput
+}
+
+type fakeWorker struct{}
+
+func (w *fakeWorker) Stop()               {}
+func (w *fakeWorker) Logs() io.ReadCloser { return io.NopCloser(nil) }
+
+func (r *fakeRuntime) Match(runtime string) bool { return true }
+func (r *fakeRuntime) Build(ctx context.Context, input *BuildInput) (*BuildOutput, error) {
+	return &BuildOutput{}, nil
+}
+func (r *fakeRuntime) ShouldRebuild(functionID string, path string) bool { return false }
+func (r *fakeRuntime) Run(ctx context.Context, input *RunInput) (Worker, error) {
+	r.inputs = append(r.inputs, input)
+	return &fakeWorker{}, nil
+}
+
+func TestInspect(t *testing.T) {
+	fake := &fakeRuntime{}
+	collection := NewCollection("", fake)
+	collection.Inspect("Api")
+	events := bus.Subscribe(&InspectEvent{})
+
+	collection.Run(context.Background(), &RunInput{FunctionID: "Api", WorkerID: "w1", Runtime: "python3.12"})
+	collection.Run(context.Background(), &RunInput{FunctionID: "Cron", WorkerID: "w2", Runtime: "nodejs20.x"})
+
+	port := InspectPort("Api")
+	if port != InspectPort("Api") || port < 9230 || port >= 9930 {
+		t.Fatalf("unexpected port %v", port)
+	}
+	// the next port is used if something is already listening
+	if fake.inputs[0].Inspect < port || fake.inputs[0].Inspect >= port+100 {
+		t.Fatalf("expected Api to be inspected on %v, got %v", port, fake.inputs[0].Inspect)
+	}
+	if fake.inputs[1].Inspect != 0 {
+		t.Fatalf("expected Cron to not be inspected")
+	}
+	evt := (<-events).(*InspectEvent)
+	if evt.FunctionID != "Api" || evt.Debugger != "debugpy" || evt.WorkerID != "w1" {
+		t.Fatalf("unexpected event %+v", evt)
+	}
+
+	collection.Inspect()
+	if !collection.Inspecting("Cron") {
+		t.Fatalf("expected every function to be inspected")
+	}
+}
+
+// shellRuntime runs a shell script as the worker.
+type shellRuntime struct {
+	script string
+}
+
+type shellWorker struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+}
+
+func (w *shellWorker) Stop()               { w.cmd.Process.Kill() }
+func (w *shellWorker) Logs() io.ReadCloser { return w.stdout }
+func (w *shellWorker) Wait() error         { return w.cmd.Wait() }
+
+func (r *shellRuntime) Match(runtime string) bool { return true }
+func (r *shellRuntime) Build(ctx context.Context, input *BuildInput) (*BuildOutput, error) {
+	return &BuildOutput{}, nil
+}
+func (r *shellRuntime) ShouldRebuild(functionID string, path string) bool { return false }
+func (r *shellRuntime) Run(ctx context.Context, input *RunInput) (Worker, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", r.script)
+	cmd.Env = input.Env
+	LimitMemory(cmd, input.Memory)
+	stdout, _ := cmd.StdoutPipe()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &shellWorker{cmd: cmd, stdout: stdout}, nil
+}
+
+func TestExited(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{"exit 0", "Runtime.ExitError"},
+		{"exit 1", "Runtime.ExitError"},
+		// killed for some other reason
+		{"kill -9 $$", "Runtime.ExitError"},
+		{"echo 'FATAL ERROR: Reached heap limit Allocation failed - JavaScript heap out of memory'; kill -6 $$", "Runtime.OutOfMemory"},
+		{"echo 'MemoryError'; exit 1", "Runtime.OutOfMemory"},
+		// printed by a worker that exits cleanly
+		{"echo 'MemoryError'; exit 0", "Runtime.ExitError"},
+	}
+	for _, test := range tests {
+		collection := NewCollection("", &shellRuntime{script: test.script})
+		worker, err := collection.Run(context.Background(), &RunInput{FunctionID: "Api", Memory: 128})
+		if err != nil {
+			t.Fatal(err)
+		}
+		outOfMemory := false
+		scanner := bufio.NewScanner(worker.Logs())
+		for scanner.Scan() {
+			outOfMemory = outOfMemory || OutOfMemory(scanner.Text())
+		}
+		exit := Exited(worker, outOfMemory)
+		if exit == nil || exit.Type != test.want {
+			t.Fatalf("%q: expected %v, got %+v", test.script, test.want, exit)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,7 +11,6 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
-	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
@@ -20,12 +18,11 @@ import (
 	"syscall"
 	"time"
 
-	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/iot"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/sst/ion/cmd/sst/mosaic/aws/iot_writer"
+	"github.com/sst/ion/cmd/sst/mosaic/aws/transport"
 )
 
 var version = "0.0.1"
@@ -36,6 +33,8 @@ var SST_FUNCTION_ID = os.Getenv("SST_FUNCTION_ID")
 var SST_FUNCTION_TIMEOUT = os.Getenv("SST_FUNCTION_TIMEOUT")
 var SST_REGION = os.Getenv("SST_REGION")
 var SST_ASSET_BUCKET = os.Getenv("SST_ASSET_BUCKET")
+var SST_LIVE_RELAY = os.Getenv("SST_LIVE_RELAY")
+var SST_LIVE_RELAY_TOKEN = os.Getenv("SST_LIVE_RELAY_TOKEN")
 
 var ENV_BLACKLIST = map[string]bool{
 	"SST_DEBUG_ENDPOINT":              true,
@@ -76,82 +75,22 @@ func run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	expire := time.Hour * 24
-	from := time.Now()
-
 	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(SST_REGION))
 	if err != nil {
 		return err
 	}
-	slog.Info("getting endpoint")
-	iotClient := iot.NewFromConfig(config)
-	endpointResp, err := iotClient.DescribeEndpoint(ctx, &iot.DescribeEndpointInput{})
-	if err != nil {
-		return err
-	}
 
-	originalURL, err := url.Parse(fmt.Sprintf("wss://%s/mqtt?X-Amz-Expires=%s", *endpointResp.EndpointAddress, strconv.FormatInt(int64(expire/time.Second), 10)))
-	if err != nil {
-		return err
-	}
-	slog.Info("found endpoint endpoint", "url", originalURL.String())
-
-	creds, err := config.Credentials.Retrieve(ctx)
-	if err != nil {
-		return err
-	}
-	sessionToken := creds.SessionToken
-	creds.SessionToken = ""
-
-	signer := v4.NewSigner()
-	req := &http.Request{
-		Method: "GET",
-		URL:    originalURL,
-	}
+	logStreamName := os.Getenv("AWS_LAMBDA_LOG_STREAM_NAME")
+	workerID := logStreamName[len(logStreamName)-32:]
 
-	presignedURL, _, err := signer.PresignHTTP(ctx, creds, req, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "iotdevicegateway", config.Region, from)
+	client, err := connect(ctx, config, workerID)
 	if err != nil {
 		return err
 	}
-	slog.Info("signed request", "url", presignedURL)
-	if sessionToken != "" {
-		presignedURL += "&X-Amz-Security-Token=" + url.QueryEscape(sessionToken)
-	}
-
-	logStreamName := os.Getenv("AWS_LAMBDA_LOG_STREAM_NAME")
-	workerID := logStreamName[len(logStreamName)-32:]
-
-	slog.Info("connecting to iot", "clientID", workerID)
-	opts := MQTT.
-		NewClientOptions().
-		AddBroker(presignedURL).
-		SetClientID(
-			workerID,
-		).
-		SetTLSConfig(&tls.Config{
-			InsecureSkipVerify: true,
-		}).
-		SetWebsocketOptions(&MQTT.WebsocketOptions{
-			ReadBufferSize:  1024 * 1000,
-			WriteBufferSize: 1024 * 1000,
-		}).
-		SetCleanSession(false).
-		SetAutoReconnect(false).
-		SetConnectionLostHandler(func(c MQTT.Client, err error) {
-			slog.Info("mqtt connection lost")
-		}).
-		SetReconnectingHandler(func(c MQTT.Client, co *MQTT.ClientOptions) {
-			slog.Info("mqtt reconnecting")
-		}).
-		SetOnConnectHandler(func(c MQTT.Client) {
-			slog.Info("mqtt connected")
-		}).
-		SetKeepAlive(time.Second * 1200).
-		SetPingTimeout(time.Second * 60)
-
-	mqttClient := MQTT.NewClient(opts)
-	if token := mqttClient.Connect(); token.Wait() && token.Error() != nil {
-		return token.Error()
+	// the relay has no message size limit so requests don't go through s3
+	s3Client := s3.NewFromConfig(config)
+	if SST_LIVE_RELAY != "" {
+		s3Client = nil
 	}
 
 	prefix := fmt.Sprintf("ion/%s/%s/%s", SST_APP, SST_STAGE, workerID)
@@ -160,12 +99,12 @@ func run() error {
 
 	requestChan := make(chan iot_writer.ReadMsg, 1000)
 	reader := iot_writer.NewReader(s3Client)
-	if token := mqttClient.Subscribe(prefix+"/request/#", 1, func(c MQTT.Client, m MQTT.Message) {
+	if err := client.Subscribe(prefix+"/request/#", func(m transport.Message) {
 		for _, msg := range reader.Read(m) {
 			requestChan <- msg
 		}
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
 	env := []string{}
@@ -180,34 +119,32 @@ func run() error {
 	if err != nil {
 		return err
 	}
-	if token := mqttClient.Publish(prefix+"/init", 1, false, initPayload); token.Wait() && token.Error() != nil {
-		return token.Error()
+	if err := client.Publish(prefix+"/init", initPayload); err != nil {
+		return err
 	}
-	if token := mqttClient.Subscribe(prefix+"/reboot", 1, func(c MQTT.Client, m MQTT.Message) {
+	if err := client.Subscribe(prefix+"/reboot", func(m transport.Message) {
 		slog.Info("received reboot message")
 		go func() {
-			if token := mqttClient.Publish(prefix+"/init", 1, false, initPayload); token.Wait() && token.Error() != nil {
-				return
-			}
+			client.Publish(prefix+"/init", initPayload)
 		}()
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
-	if token := mqttClient.Subscribe(prefix+"/kill", 1, func(c MQTT.Client, m MQTT.Message) {
+	if err := client.Subscribe(prefix+"/kill", func(m transport.Message) {
 		slog.Info("received kill message")
 		cancel()
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
 	ack := make(chan struct{})
-	if token := mqttClient.Subscribe(prefix+"/ack", 1, func(c MQTT.Client, m MQTT.Message) {
+	if err := client.Subscribe(prefix+"/ack", func(m transport.Message) {
 		go func() {
 			ack <- struct{}{}
 		}()
-	}); token.Wait() && token.Error() != nil {
-		return token.Error()
+	}); err != nil {
+		return err
 	}
 
 	sigs := make(chan os.Signal)
@@ -218,7 +155,7 @@ func run() error {
 		cancel()
 	}()
 	defer func() {
-		mqttClient.Publish(prefix+"/shutdown", 1, false, initPayload).Wait()
+		client.Publish(prefix+"/shutdown", initPayload)
 	}()
 
 	timeout := time.Second * 8
@@ -254,7 +191,7 @@ func run() error {
 				return
 			}
 		}()
-		mqttClient.Publish(prefix+"/init", 1, false, initPayload).Wait()
+		client.Publish(prefix+"/init", initPayload)
 		for {
 			conn, err := net.Dial("tcp", LAMBDA_RUNTIME_API)
 			if err != nil {
@@ -265,7 +202,7 @@ func run() error {
 				reportError(requestID, "it does not seem like sst dev is running")
 				break
 			}
-			writer := iot_writer.New(mqttClient, s3Client, SST_ASSET_BUCKET, prefix+"/response/"+msgID)
+			writer := iot_writer.New(client, s3Client, SST_ASSET_BUCKET, prefix+"/response/"+msgID)
 			err = forwardResponse(requestContext, writer, conn)
 			if err != nil {
 				slog.Error("failed to forward response", "error", err)
@@ -294,6 +231,21 @@ func reportError(requestID string, err string) {
 	)
 }
 
+// connect uses the websocket relay in SST_LIVE_RELAY if it is set, otherwise
+// AWS IoT.
+func connect(ctx context.Context, config aws.Config, workerID string) (transport.Transport, error) {
+	if SST_LIVE_RELAY != "" {
+		slog.Info("connecting to relay", "url", SST_LIVE_RELAY)
+		return transport.NewRelay(ctx, SST_LIVE_RELAY, transport.RelayOptions{
+			Token: SST_LIVE_RELAY_TOKEN,
+		})
+	}
+	slog.Info("connecting to iot", "clientID", workerID)
+	return transport.NewIoT(ctx, config, transport.MQTTOptions{
+		ClientID: workerID,
+	})
+}
+
 func forwardRequest(ctx context.Context, requestChan chan iot_writer.ReadMsg, conn net.Conn) (string, *http.Request, error) {
 	var buffer bytes.Buffer
 	multiWriter := io.MultiWriter(conn, &buffer)
@@ -313,9 +265,6 @@ func forwardRequest(ctx context.Context, requestChan chan iot_writer.ReadMsg, co
 	}
 }
 
-var cfg, _ = config.LoadDefaultConfig(context.TODO())
-var s3Client = s3.NewFromConfig(cfg)
-
 func forwardResponse(ctx context.Context, writer *iot_writer.IoTWriter, conn net.Conn) error {
 	slog.Info("forwarding response")
 	buf := make([]byte, 1024*5)
@@ -1343,7 +1343,17 @@ export class Function extends Component implements Link.Linkable {
     }
 
     function normalizeTimeout() {
-      return output(args.timeout).apply((timeout) => timeout ?? "20 seconds");
+      return output(args.timeout).apply((timeout) => {
+        // leave time to step through the function in a debugger
+        const inspect = process.env.SST_INSPECT;
+        if (
+          $dev &&
+          inspect &&
+          (inspect === "*" || inspect.split(",").includes(name))
+        )
+          return "900 seconds";
+        return timeout ?? "20 seconds";
+      });
     }
 
     function normalizeMemory() {
@@ -1374,6 +1384,11 @@ export class Function extends Component implements Link.Linkable {
           if (process.env.SST_FUNCTION_TIMEOUT) {
             result.SST_FUNCTION_TIMEOUT = process.env.SST_FUNCTION_TIMEOUT;
           }
+          if (process.env.SST_LIVE_RELAY) {
+            result.SST_LIVE_RELAY = process.env.SST_LIVE_RELAY;
+            if (process.env.SST_LIVE_RELAY_TOKEN)
+              result.SST_LIVE_RELAY_TOKEN = process.env.SST_LIVE_RELAY_TOKEN;
+          }
         }
         return result;
       });
@@ -227,6 +227,143 @@ export interface App {
    *
    */
   home: "aws" | "cloudflare" | "local";
+
+  /**
+   * Commands to run around a deploy or remove. They are run in the root of your app with
+   * a shell, so you can chain commands or call your package scripts.
+   *
+   * - `preDeploy`: Runs before `sst deploy` makes any changes.
+   * - `postDeploy`: Runs after `sst deploy` succeeds.
+   * - `onFailure`: Runs after `sst deploy` or `sst remove` fails, including when one of the other hooks fails.
+   * - `preRemove`: Runs before `sst remove` makes any changes.
+   *
+   * The commands get your linked resources in the same way as [`sst shell`](/docs/reference/cli#shell).
+   * They also get the outputs of your app as JSON in `SST_OUTPUTS`, and the name of the hook
+   * in `SST_HOOK`. For the pre hooks, these are the outputs from the previous deploy. The
+   * `onFailure` hook also gets the errors as JSON in `SST_ERRORS`.
+   *
+   * If a hook fails, the deploy or remove is marked as failed.
+   *
+   * Hooks don't run for the deploys that `sst dev` makes as you change your app.
+   *
+   * @example
+   *
+   * Run your migrations after a deploy and post to a channel if it fails.
+   *
+   * ```ts
+   * {
+   *   hooks: {
+   *     postDeploy: "pnpm run migrate && pnpm run smoke",
+   *     onFailure: "node scripts/notify.mjs"
+   *   }
+   * }
+   * ```
+   */
+  hooks?: {
+    preDeploy?: string;
+    postDeploy?: string;
+    onFailure?: string;
+    preRemove?: string;
+  };
+
+  /**
+   * URLs that get a `POST` when a `deploy`, `remove`, or `refresh` starts, succeeds, or fails.
+   *
+   * By default the body is JSON with the `app`, `stage`, `updateID`, `command`, the `summary`
+   * of the resource changes, the `errors`, and the `duration` in seconds. Set the `format` to
+   * `slack` or `teams` to post a message that their incoming webhooks accept instead.
+   *
+   * Use `events` to only get some of `start`, `success`, and `failure`.
+   *
+   * They are not sent for the deploys that `sst dev` makes as you change your app.
+   *
+   * @example
+   *
+   * ```ts
+   * {
+   *   webhooks: [
+   *     { url: "https://example.com/deploys" },
+   *     {
+   *       url: process.env.SLACK_WEBHOOK_URL,
+   *       format: "slack",
+   *       events: ["failure"]
+   *     }
+   *   ]
+   * }
+   * ```
+   */
+  webhooks?: {
+    url: string;
+    format?: "json" | "slack" | "teams";
+    events?: ("start" | "success" | "failure")[];
+  }[];
+
+  /**
+   * Protect stages from having their resources deleted or replaced by accident.
+   *
+   * On a protected stage, `sst remove` and any deploy that would delete or replace resources
+   * ask you to type in the name of the stage first. In CI, or any time there's no terminal,
+   * they fail unless you pass in `--allow-destroy`.
+   *
+   * Set it to `true` to protect the current stage, or pass in a list of stage names, where
+   * `*` matches anything.
+   *
+   * You can also pass in a list of URN patterns for resources that can never be deleted or
+   * replaced in any stage, not even with `--allow-destroy`. This covers the resources that
+   * are created by a matching component too.
+   *
+   * @example
+   *
+   * ```ts
+   * {
+   *   protect: {
+   *     stages: ["production", "staging-*"],
+   *     resources: ["*::sst:aws:Postgres::Database*"]
+   *   }
+   * }
+   * ```
+   *
+   * Or use the stage to decide.
+   *
+   * ```ts
+   * {
+   *   protect: input.stage === "production"
+   * }
+   * ```
+   */
+  protect?:
+    | boolean
+    | {
+        stages?: string[];
+        resources?: string[];
+      };
+
+  /**
+   * Tags that every resource has to have, like a cost center or an owner.
+   *
+   * When set, `sst deploy` checks the resources it's about to create or update and fails if
+   * any of them are missing one of these tags. `sst diff` and `sst dev` print a warning for them
+   * instead.
+   * Resources that don't support tags are skipped.
+   *
+   * The easiest way to add them to every resource is through the `defaultTags` of your provider.
+   *
+   * ```ts
+   * {
+   *   requiredTags: ["cost-center", "owner"],
+   *   providers: {
+   *     aws: {
+   *       defaultTags: {
+   *         tags: { "cost-center": "platform", owner: "infra" }
+   *       }
+   *     }
+   *   }
+   * }
+   * ```
+   *
+   * Run `sst tags report` to check the resources that are already deployed.
+   */
+  requiredTags?: string[];
 }
 
 export interface AppInput {